@@ -0,0 +1,25 @@
+package memdigest
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+)
+
+// algorithm describes a hash algorithm a store can use.
+type algorithm struct {
+	name string
+	size int
+	newHash func() hash.Hash
+}
+
+// knownAlgorithms are the hash algorithms that can be referred to by name.
+var knownAlgorithms = map[string]algorithm{
+	algorithmSHA1:       {algorithmSHA1,       sha1.Size,      sha1.New},
+	algorithmSHA224:     {algorithmSHA224,     sha256.Size224, sha256.New224},
+	algorithmSHA256:     {algorithmSHA256,     sha256.Size,    sha256.New},
+	algorithmSHA384:     {algorithmSHA384,     sha512.Size384, sha512.New384},
+	algorithmSHA512:     {algorithmSHA512,     sha512.Size,    sha512.New},
+	algorithmSHA512_256: {algorithmSHA512_256, sha512.Size256, sha512.New512_256},
+}
@@ -0,0 +1,225 @@
+package memdigest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The archive format, written by WriteTo and read by ReadFrom, is (with all integers big-endian):
+//
+//	magic      8 bytes: "MEMDIGST"
+//	version    1 byte:  0x01
+//	name size  1 byte:  the length of the algorithm name
+//	name       the algorithm name (ex: "SHA-256")
+//	size       2 bytes: the length of each digest
+//	count      8 bytes: how many entries follow
+//
+// Followed by ‘count’ entries, each of which is:
+//
+//	digest     ‘size’ bytes: the digest of the content, in binary form
+//	length     8 bytes: the length of the content
+//	content    ‘length’ bytes: the content
+//
+// Entries are in order of digest.
+const (
+	archiveMagic string = "MEMDIGST"
+	archiveVersion byte = 0x01
+)
+
+// ArchiveError is the error returned by ReadFrom when an archive is corrupted, or is otherwise unusable.
+//
+// When ReadFrom returns an ArchiveError, nothing from the archive was stored.
+type ArchiveError struct {
+	Entry int // the index of the entry the problem is with, or -1 if it is with the header
+	Offset int64 // the offset, in the archive, where the problem was found
+	Reason string
+}
+
+func (receiver ArchiveError) Error() string {
+	if receiver.Entry < 0 {
+		return fmt.Sprintf("memdigest: Bad Archive: header (at byte offset %d): %s", receiver.Offset, receiver.Reason)
+	}
+
+	return fmt.Sprintf("memdigest: Bad Archive: entry #%d (at byte offset %d): %s", receiver.Entry, receiver.Offset, receiver.Reason)
+}
+
+// WriteTo makes *memdigest.Store fit the io.WriterTo interface.
+//
+// WriteTo writes every piece of content in the store to ‘w’, as an archive that ReadFrom can read back.
+// What is written is a consistent view of the store, taken when WriteTo is called.
+func (receiver *Store) WriteTo(w io.Writer) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	if 255 < len(receiver.algorithm) {
+		return 0, fmt.Errorf("memdigest: Algorithm Name Too Long: %q", receiver.algorithm)
+	}
+
+	items := receiver.items()
+
+	counter := countingWriter{writer:w}
+	bw := bufio.NewWriter(&counter)
+
+	{
+		var header bytes.Buffer
+
+		header.WriteString(archiveMagic)
+		header.WriteByte(archiveVersion)
+		header.WriteByte(byte(len(receiver.algorithm)))
+		header.WriteString(receiver.algorithm)
+		binary.Write(&header, binary.BigEndian, uint16(receiver.size))
+		binary.Write(&header, binary.BigEndian, uint64(len(items)))
+
+		if _, err := bw.Write(header.Bytes()); nil != err {
+			return counter.n, err
+		}
+	}
+
+	for _, item := range items {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(item.value)))
+
+		if _, err := bw.WriteString(item.key); nil != err {
+			return counter.n, err
+		}
+		if _, err := bw.Write(length[:]); nil != err {
+			return counter.n, err
+		}
+		if _, err := bw.WriteString(item.value); nil != err {
+			return counter.n, err
+		}
+	}
+
+	err := bw.Flush()
+
+	return counter.n, err
+}
+
+// ReadFrom makes *memdigest.Store fit the io.ReaderFrom interface.
+//
+// ReadFrom reads an archive (written by WriteTo) from ‘r’, and stores every piece of content in it.
+//
+// The digest of every piece of content is re-calculated, and checked against the digest in the archive.
+// If the archive is corrupted in any way, ReadFrom returns a memdigest.ArchiveError that says where, and stores nothing.
+func (receiver *Store) ReadFrom(r io.Reader) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+	if receiver.frozen.Load() {
+		return 0, ErrReadOnly
+	}
+
+	counter := countingReader{reader:bufio.NewReader(r)}
+
+	headerError := func(reason string, a ...interface{}) error {
+		return ArchiveError{Entry:-1, Offset:counter.n, Reason:fmt.Sprintf(reason, a...)}
+	}
+
+	{
+		var p [len(archiveMagic)+2]byte
+		if _, err := io.ReadFull(&counter, p[:]); nil != err {
+			return counter.n, headerError("could not read magic and version: %s", err)
+		}
+
+		if magic := string(p[:len(archiveMagic)]); archiveMagic != magic {
+			return counter.n, headerError("bad magic: expected %q, but actually got %q", archiveMagic, magic)
+		}
+		if version := p[len(archiveMagic)]; archiveVersion != version {
+			return counter.n, headerError("unsupported version: expected %d, but actually got %d", archiveVersion, version)
+		}
+
+		name := make([]byte, p[len(archiveMagic)+1])
+		if _, err := io.ReadFull(&counter, name); nil != err {
+			return counter.n, headerError("could not read algorithm: %s", err)
+		}
+		if receiver.algorithm != string(name) {
+			return counter.n, headerError("wrong algorithm: expected %q, but actually got %q", receiver.algorithm, name)
+		}
+	}
+
+	var count uint64
+	{
+		var size uint16
+		if err := binary.Read(&counter, binary.BigEndian, &size); nil != err {
+			return counter.n, headerError("could not read digest size: %s", err)
+		}
+		if receiver.size != int(size) {
+			return counter.n, headerError("wrong digest size: expected %d, but actually got %d", receiver.size, size)
+		}
+
+		if err := binary.Read(&counter, binary.BigEndian, &count); nil != err {
+			return counter.n, headerError("could not read entry count: %s", err)
+		}
+	}
+
+	var items []item
+
+	for i := uint64(0); i < count; i++ {
+		offset := counter.n
+
+		entryError := func(reason string, a ...interface{}) error {
+			return ArchiveError{Entry:int(i), Offset:offset, Reason:fmt.Sprintf(reason, a...)}
+		}
+
+		digest := make([]byte, receiver.size)
+		if _, err := io.ReadFull(&counter, digest); nil != err {
+			return counter.n, entryError("could not read digest: %s", err)
+		}
+
+		var length uint64
+		if err := binary.Read(&counter, binary.BigEndian, &length); nil != err {
+			return counter.n, entryError("could not read content length: %s", err)
+		}
+
+		var content strings.Builder
+		h := receiver.newHash()
+
+		n, err := io.CopyN(io.MultiWriter(&content, h), &counter, int64(length))
+		if nil != err {
+			return counter.n, entryError("could only read %d of the %d bytes of content: %s", n, length, err)
+		}
+
+		if actual := h.Sum(nil); !bytes.Equal(digest, actual) {
+			return counter.n, entryError("digest mismatch: the archive says %x, but the content actually has %x", digest, actual)
+		}
+
+		items = append(items, item{key:string(digest), value:content.String()})
+	}
+
+	for _, item := range items {
+		if _, err := receiver.insert(item.key, item.value, 0); nil != err {
+			return counter.n, err
+		}
+	}
+
+	return counter.n, nil
+}
+
+type countingWriter struct {
+	writer io.Writer
+	n int64
+}
+
+func (receiver *countingWriter) Write(p []byte) (int, error) {
+	n, err := receiver.writer.Write(p)
+	receiver.n += int64(n)
+
+	return n, err
+}
+
+type countingReader struct {
+	reader io.Reader
+	n int64
+}
+
+func (receiver *countingReader) Read(p []byte) (int, error) {
+	n, err := receiver.reader.Read(p)
+	receiver.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,132 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"bytes"
+
+	"testing"
+)
+
+func TestSHA1WriteToReadFrom(t *testing.T) {
+
+	contents := []string{"", "Hello world!", "😏😐👾🤖😈", "apple", "BANANA"}
+
+	var original memdigest.SHA1
+	for _, content := range contents {
+		if _, err := original.Store([]byte(content)); nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+	}
+
+	var archive bytes.Buffer
+
+	n, err := original.WriteTo(&archive)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	if expected, actual := int64(archive.Len()), n; expected != actual {
+		t.Errorf("The number of bytes written that was actually returned was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	var restored memdigest.SHA1
+
+	n, err = restored.ReadFrom(bytes.NewReader(archive.Bytes()))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	if expected, actual := int64(archive.Len()), n; expected != actual {
+		t.Errorf("The number of bytes read that was actually returned was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	for digest := range original.All() {
+		expected, _ := original.Load(digest[:])
+
+		actual, found := restored.Load(digest[:])
+		if !found {
+			t.Errorf("Expected restored content to exist, but it actually didn't: %x", digest)
+			continue
+		}
+		if expected != actual {
+			t.Errorf("The restored content was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+
+	if expected, actual := len(contents), restored.Stats().Blobs; expected != actual {
+		t.Errorf("The number of restored blobs was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
+
+func TestSHA1ReadFromCorrupted(t *testing.T) {
+
+	var original memdigest.SHA1
+	original.Store([]byte("apple"))
+	original.Store([]byte("BANANA"))
+
+	var archive bytes.Buffer
+	if _, err := original.WriteTo(&archive); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	tests := []struct{
+		Corrupt func([]byte) []byte
+		ExpectedEntry int
+	}{
+		{
+			Corrupt: func(p []byte) []byte { p[0] = 'X'; return p },
+			ExpectedEntry: -1,
+		},
+		{
+			Corrupt: func(p []byte) []byte { p[8] = 0x7f; return p },
+			ExpectedEntry: -1,
+		},
+		{
+			Corrupt: func(p []byte) []byte { p[len(p)-1] ^= 0xff; return p },
+			ExpectedEntry: 1,
+		},
+		{
+			Corrupt: func(p []byte) []byte { return p[:len(p)-2] },
+			ExpectedEntry: 1,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		corrupted := test.Corrupt(append([]byte(nil), archive.Bytes()...))
+
+		var restored memdigest.SHA1
+
+		_, err := restored.ReadFrom(bytes.NewReader(corrupted))
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			continue
+		}
+
+		archiveError, casted := err.(memdigest.ArchiveError)
+		if !casted {
+			t.Errorf("For test #%d, expected error to be memdigest.ArchiveError, but actually wasn't: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedEntry, archiveError.Entry; expected != actual {
+			t.Errorf("For test #%d, the entry the error was actually about was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+			t.Logf("ERROR: %s", err)
+			continue
+		}
+
+		if expected, actual := 0, restored.Stats().Blobs; expected != actual {
+			t.Errorf("For test #%d, did not expect anything from a corrupted archive to be stored, but %d blob(s) actually were.", testNumber, actual)
+			continue
+		}
+	}
+}
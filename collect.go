@@ -0,0 +1,108 @@
+package memdigest
+
+// CollectReport reports what Collect freed.
+type CollectReport struct {
+	Reachable int // how many pieces of content were reachable, and so were kept
+
+	Blobs int   // how many pieces of content were freed
+	Bytes int64 // the total length of the content that was freed
+}
+
+// Collect does a mark-and-sweep garbage collection of the store.
+//
+// Starting from the content whose digests are in ‘roots’, Collect marks content as reachable, following the
+// digests that ‘links’ extracts from each piece of reachable content. (The content passed to ‘links’ is a copy.)
+// Content that is pinned (see Pin) is also treated as a root.
+//
+// Then every piece of content that was not reachable is freed, and a report of what was freed is returned.
+//
+// Content that is stored while Collect is running is never freed by that Collect.
+// And if the store is frozen (see Freeze), then nothing is freed.
+//
+// Example
+//
+//	report := mem.Collect(roots, func(content []byte) [][]byte {
+//		var children [][]byte
+//	
+//		// ...
+//	
+//		return children
+//	})
+func (receiver *Store) Collect(roots [][]byte, links func(content []byte) [][]byte) CollectReport {
+	if nil == receiver {
+		return CollectReport{}
+	}
+
+	// Only content that was stored before marking started is a candidate for being freed.
+	var candidates []string
+	var queue []string
+	for i := range receiver.shards {
+		shard := &receiver.shards[i]
+
+		shard.mutex.RLock()
+		for key, e := range shard.data {
+			candidates = append(candidates, key)
+			if 0 < e.pins {
+				queue = append(queue, key)
+			}
+		}
+		shard.mutex.RUnlock()
+	}
+
+	for _, root := range roots {
+		queue = append(queue, string(root))
+	}
+
+	// Mark.
+	reachable := map[string]struct{}{}
+	for 0 < len(queue) {
+		key := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if receiver.size != len(key) {
+			continue
+		}
+		if _, marked := reachable[key]; marked {
+			continue
+		}
+
+		e := receiver.shard(key).get(key)
+		if nil == e {
+			continue
+		}
+
+		reachable[key] = struct{}{}
+
+		if nil == links {
+			continue
+		}
+
+		for _, child := range links([]byte(e.value)) {
+			queue = append(queue, string(child))
+		}
+	}
+
+	// Sweep.
+	var report CollectReport
+	report.Reachable = len(reachable)
+
+	if receiver.frozen.Load() {
+		return report
+	}
+
+	for _, key := range candidates {
+		if _, marked := reachable[key]; marked {
+			continue
+		}
+
+		size, removed := receiver.shard(key).sweepKey(key, &receiver.lru)
+		if !removed {
+			continue
+		}
+
+		report.Blobs++
+		report.Bytes += int64(size)
+	}
+
+	return report
+}
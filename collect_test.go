@@ -0,0 +1,71 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"testing"
+)
+
+func TestSHA1Collect(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	// A manifest is the hexadecimal SHA-1 digests of its children, one per line.
+	links := func(content []byte) [][]byte {
+		var children [][]byte
+
+		for _, line := range strings.Split(string(content), "\n") {
+			digest, err := hex.DecodeString(line)
+			if nil != err {
+				continue
+			}
+			children = append(children, digest)
+		}
+
+		return children
+	}
+
+	apple, _ := mem.Store([]byte("apple"))
+	banana, _ := mem.Store([]byte("BANANA"))
+	cherry, _ := mem.Store([]byte("Cherry"))
+	date, _ := mem.Store([]byte("dATE"))
+
+	inner, _ := mem.Store([]byte(fmt.Sprintf("%x\n%x", banana, cherry)))
+	root, _ := mem.Store([]byte(fmt.Sprintf("%x\n%x", apple, inner)))
+
+	pinned, _ := mem.Store([]byte("pinned"))
+	if err := mem.Pin(pinned[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	report := mem.Collect([][]byte{root[:]}, links)
+
+	if expected, actual := 6, report.Reachable; expected != actual {
+		t.Errorf("The number of reachable pieces of content was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+	if expected, actual := 1, report.Blobs; expected != actual {
+		t.Errorf("The number of freed pieces of content was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+	if expected, actual := int64(len("dATE")), report.Bytes; expected != actual {
+		t.Errorf("The number of freed bytes was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	if _, found := mem.Load(date[:]); found {
+		t.Errorf("Did not expect unreachable content to still exist, but it actually did.")
+	}
+	for _, digest := range [][20]byte{apple, banana, cherry, inner, root, pinned} {
+		if _, found := mem.Load(digest[:]); !found {
+			t.Errorf("Expected reachable content to still exist, but it actually didn't: %x", digest)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package memdigest
+
+import (
+	"io"
+)
+
+// Content is what opening content (with Open, OpenLocation, or OpenMultihash) returns.
+//
+// Content reads straight from the stored string, so opening content does not copy it.
+//
+// Besides fitting the digestfs_driver.Content interface, Content also fits the io.Reader, io.ReaderAt, io.Seeker, and io.WriterTo interfaces,
+// and has a Size method; so that, for example, http.ServeContent can serve (ranges of) it, and io.Copy can copy it with a single Write.
+//
+// ReadAt and Size can be called concurrently. Read, Seek, and WriteTo share a position, and so cannot.
+//
+// Example
+//
+//	content, err := mem.Open("SHA-256", digest)
+//	if nil != err {
+//		return err
+//	}
+//	defer content.Close()
+//
+//	http.ServeContent(w, r, "", modtime, content.(*memdigest.Content))
+type Content struct {
+	value string
+	position int64
+}
+
+func newContent(value string) *Content {
+	return &Content{
+		value: value,
+	}
+}
+
+// Close makes *memdigest.Content fit the io.Closer interface.
+//
+// There is nothing to release, so Close does nothing.
+func (receiver *Content) Close() error {
+	return nil
+}
+
+// Len returns the length of the content, in bytes.
+func (receiver *Content) Len() int {
+	if nil == receiver {
+		return 0
+	}
+
+	return len(receiver.value)
+}
+
+// Read makes *memdigest.Content fit the io.Reader interface.
+func (receiver *Content) Read(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, io.EOF
+	}
+
+	if int64(len(receiver.value)) <= receiver.position {
+		return 0, io.EOF
+	}
+
+	n := copy(p, receiver.value[receiver.position:])
+	receiver.position += int64(n)
+
+	return n, nil
+}
+
+// ReadAt makes *memdigest.Content fit the io.ReaderAt interface.
+func (receiver *Content) ReadAt(p []byte, offset int64) (int, error) {
+	if nil == receiver {
+		return 0, io.EOF
+	}
+
+	if offset < 0 {
+		return 0, errNegativeOffset
+	}
+
+	if int64(len(receiver.value)) <= offset {
+		return 0, io.EOF
+	}
+
+	n := copy(p, receiver.value[offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// Seek makes *memdigest.Content fit the io.Seeker interface.
+//
+// Seeking past the end of the content is allowed; Read then returns io.EOF.
+func (receiver *Content) Seek(offset int64, whence int) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = receiver.position + offset
+	case io.SeekEnd:
+		position = int64(len(receiver.value)) + offset
+	default:
+		return 0, errBadWhence
+	}
+
+	if position < 0 {
+		return 0, errNegativeOffset
+	}
+
+	receiver.position = position
+
+	return position, nil
+}
+
+// Size returns the length of the content, in bytes. (It is the same as Len, but as an int64.)
+func (receiver *Content) Size() int64 {
+	if nil == receiver {
+		return 0
+	}
+
+	return int64(len(receiver.value))
+}
+
+// WriteTo makes *memdigest.Content fit the io.WriterTo interface.
+//
+// It writes the rest of the content (from the position that Read and Seek share) to ‘w’.
+// If ‘w’ fits the io.StringWriter interface, then the content is written to it without being copied.
+func (receiver *Content) WriteTo(w io.Writer) (int64, error) {
+	if nil == receiver {
+		return 0, nil
+	}
+
+	if int64(len(receiver.value)) <= receiver.position {
+		return 0, nil
+	}
+
+	s := receiver.value[receiver.position:]
+
+	n, err := io.WriteString(w, s)
+	receiver.position += int64(n)
+	if nil == err && n < len(s) {
+		err = io.ErrShortWrite
+	}
+
+	return int64(n), err
+}
@@ -0,0 +1,137 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func TestContent(t *testing.T) {
+
+	var mem memdigest.SHA256
+
+	const value string = "Hello world!"
+
+	digest, err := mem.Store([]byte(value))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	opened, err := mem.Open("SHA-256", string(digest[:]))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer opened.Close()
+
+	content, ok := opened.(*memdigest.Content)
+	if !ok {
+		t.Fatalf("Expected a *memdigest.Content, but actually got a %T.", opened)
+	}
+
+	var _ io.ReadSeeker = content
+	var _ io.ReaderAt = content
+	var _ io.WriterTo = content
+
+	if expected, actual := int64(len(value)), content.Size(); expected != actual {
+		t.Errorf("The size of the content was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	{
+		var p [5]byte
+
+		n, err := content.ReadAt(p[:], 6)
+		if nil != err {
+			t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+
+		if expected, actual := "world", string(p[:n]); expected != actual {
+			t.Errorf("What was read at the offset was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+
+	{
+		position, err := content.Seek(-6, io.SeekEnd)
+		if nil != err {
+			t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+		if expected, actual := int64(6), position; expected != actual {
+			t.Errorf("The position returned by Seek was not what was expected.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+
+		var builder strings.Builder
+
+		n, err := io.Copy(&builder, content)
+		if nil != err {
+			t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+
+		if expected, actual := "world!", builder.String(); expected != actual {
+			t.Errorf("What was copied after seeking was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+		if expected, actual := int64(6), n; expected != actual {
+			t.Errorf("The number of bytes copied was not what was expected.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+	}
+
+	{
+		_, err := content.Seek(-1, io.SeekStart)
+		if nil == err {
+			t.Errorf("Expected an error when seeking to before the start, but did not actually get one.")
+		}
+	}
+}
+
+func TestContentServeContent(t *testing.T) {
+
+	var mem memdigest.SHA256
+
+	digest, err := mem.Store([]byte("Hello world!"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	location, err := mem.Location(digest[:])
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	opened, err := mem.OpenLocation(location)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	request := httptest.NewRequest("GET", "/hello.txt", nil)
+	request.Header.Set("Range", "bytes=6-10")
+
+	recorder := httptest.NewRecorder()
+
+	http.ServeContent(recorder, request, "hello.txt", time.Time{}, opened.(*memdigest.Content))
+
+	if expected, actual := http.StatusPartialContent, recorder.Code; expected != actual {
+		t.Errorf("The HTTP status code was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	if expected, actual := "world", recorder.Body.String(); expected != actual {
+		t.Errorf("The HTTP response body was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+}
@@ -0,0 +1,52 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"github.com/reiver/go-digestfs"
+
+	"testing"
+)
+
+func TestSHA1Delete(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	apple, err := mem.Store([]byte("apple"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	banana, err := mem.Store([]byte("BANANA"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if removed, err := mem.Delete(apple[:]); nil != err || !removed {
+		t.Errorf("Expected the first Delete to remove the content, but it actually didn't: removed=%t err=%v", removed, err)
+	}
+	if removed, err := mem.Delete(apple[:]); nil != err || removed {
+		t.Errorf("Expected the second Delete to not remove anything, but it actually did: removed=%t err=%v", removed, err)
+	}
+
+	if _, found := mem.Load(apple[:]); found {
+		t.Errorf("Did not expect deleted content to still exist, but it actually did.")
+	}
+
+	{
+		_, err := mem.Open("SHA-1", string(apple[:]))
+		switch err.(type) {
+		case digestfs.ContentNotFound:
+			// Nothing here.
+		default:
+			t.Errorf("Expected error to be ContentNotFound, but actually wasn't: (%T) %q", err, err)
+		}
+	}
+
+	if value, found := mem.Load(banana[:]); !found || "BANANA" != value {
+		t.Errorf("Expected other content to be unaffected by Delete, but it actually wasn't: found=%t value=%q", found, value)
+	}
+
+	if removed, err := mem.Delete([]byte("too short")); nil != err || removed {
+		t.Errorf("Expected Delete with a bad digest to not remove anything, but it actually did: removed=%t err=%v", removed, err)
+	}
+}
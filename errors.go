@@ -4,6 +4,19 @@ import (
 	"errors"
 )
 
+// ErrReadOnly is the error returned when trying to change a store that is read-only (see Freeze), or a Snapshot.
+var ErrReadOnly error = errors.New("memdigest: Read Only")
+
 var (
+	errBadWhence = errors.New("memdigest: Bad Whence")
+	errLogNotOpen = errors.New("memdigest: Log Not Open")
+	errLogOpen = errors.New("memdigest: Log Already Open")
+	errNegativeOffset = errors.New("memdigest: Negative Offset")
+	errNegativeSize = errors.New("memdigest: Negative Size")
 	errNilReceiver = errors.New("memsha1: Nil Receiver")
+	errNoAlgorithms = errors.New("memdigest: No Algorithms")
+	errNonPositiveInterval = errors.New("memdigest: Non-Positive Interval")
+	errNonPositiveTTL = errors.New("memdigest: Non-Positive TTL")
+	errNotPinned = errors.New("memdigest: Not Pinned")
+	errWriterClosed = errors.New("memdigest: Writer Closed")
 )
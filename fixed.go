@@ -0,0 +1,475 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"expvar"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// namedAlgorithm names the hash algorithm (one of knownAlgorithms) that a fixed store uses.
+type namedAlgorithm interface {
+	algorithmName() string
+}
+
+// fixed is what SHA1 and SHA256 have in common: a Store, for the algorithm named by ‘A’, that is created the first time it is used
+// (so that the zero value is ready to use).
+//
+// SHA1 and SHA256 embed it, and so get all of its methods. Only the methods that return digests as fixed-size arrays
+// (ex: [sha1.Size]byte, rather than string) are on SHA1 and SHA256 themselves.
+type fixed[A namedAlgorithm] struct {
+	once sync.Once
+	store Store
+}
+
+func (receiver *fixed[A]) storage() *Store {
+	receiver.once.Do(func(){
+		known := knownAlgorithms[receiver.algorithm()]
+
+		receiver.store.init(known.name, known.size, known.newHash)
+	})
+
+	return &receiver.store
+}
+
+// algorithm returns the name of the hash algorithm (ex: "SHA-1"). It does not need ‘receiver’ to be non-nil.
+func (receiver *fixed[A]) algorithm() string {
+	var named A
+
+	return named.algorithmName()
+}
+
+// registerFixed registers a digestfs_driver.Mounter, under the name ‘name’, that accepts a ‘P’ (ex: *memdigest.SHA1)
+// (optionally followed by "read-only", to freeze the store, see Freeze, and "multihash", see SetCreateMultihash).
+func registerFixed[T any, P interface{ *T; digestfs_driver.MountPoint; storage() *Store }](name string) {
+	var mounter digestfs_driver.Mounter = digestfs_driver.MounterFunc(func(args ...interface{}) (digestfs_driver.MountPoint, error){
+		if actual := len(args); actual < 1 {
+			return nil, fmt.Errorf("memdigest: Wrong Number Of Arguments: expected at least 1, but actually got %d", actual)
+		}
+
+		arg0 := args[0]
+
+		options, err := parseMountOptions(args[1:])
+		if nil != err {
+			return nil, err
+		}
+
+		mem, casted := arg0.(P)
+		if !casted {
+			return nil, fmt.Errorf("memdigest: Wrong Type: expected %T, but actually got %T", P(nil), arg0)
+		}
+		if nil == mem {
+			return nil, errNilReceiver
+		}
+
+		options.apply(mem.storage())
+		mem.storage().mounted(name)
+
+		return mem, nil
+	})
+
+	digestfs_driver.Registry.Register(mounter, name)
+}
+
+
+// CloseLog stops the store from being durable (see OpenLog), and closes the log.
+func (receiver *fixed[A]) CloseLog() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().CloseLog()
+}
+
+// Collect does a mark-and-sweep garbage collection of the store, and returns a report of what it freed.
+//
+// ‘roots’ are the digests of the content to keep, and ‘links’ extracts, from a piece of content, the digests
+// of the other content it refers to. Everything not reachable from ‘roots’ (and not pinned) is freed.
+func (receiver *fixed[A]) Collect(roots [][]byte, links func(content []byte) [][]byte) CollectReport {
+	if nil == receiver {
+		return CollectReport{}
+	}
+
+	return receiver.storage().Collect(roots, links)
+}
+
+// CompactLog rewrites the log (see OpenLog) so that it only has the content that is currently stored.
+func (receiver *fixed[A]) CompactLog() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().CompactLog()
+}
+
+// Create makes the store fit the digestfs_driver.MountPoint interface.
+//
+// Create is very similar to Store, in that it stores ‘content’ and returns the digest of ‘content’.
+//
+// Example
+//
+// Here is an example of it being used:
+//
+//	var mem *memdigest.SHA1
+//	
+//	// ...
+//	
+//	var content []byte = []byte("The request has been fulfilled and resulted in a new resource being created.")
+//	
+//	// ...
+//	
+//	algorithm, digest, err := mem.Create(content)
+//
+// The returned digest is in binary form, not hexadecimal.
+//
+// In the case of our example, it will be:
+//
+//	[64]byte{0x0c, 0xe9, 0xff, 0x3b, 0x12, 0xaf, 0xdb, 0x31, 0x61, 0x75, 0x1e, 0x3a, 0xb4, 0x49, 0x87, 0x62, 0x95, 0x23, 0x63, 0x3d}
+//
+// If you want to convert it to hexadecimal, you can do so with code such as:
+//
+//	hexadecimalDigest := fmt.Sprintf("%x", digest)
+//
+// Which will return the string:
+//
+//	"0ce9ff3b12afdb3161751e3ab44987629523633d"
+//
+// More typically though, this would be use Create through package digestfs.
+func (receiver *fixed[A]) Create(p []byte) (algorithm string, digest string, err error) {
+	if nil == receiver {
+		return receiver.algorithm(), "", errNilReceiver
+	}
+
+	return receiver.storage().Create(p)
+}
+
+// Delete removes the content whose digest is ‘digest’, and returns whether anything was removed.
+//
+// Unlike Unmount, which removes all content, Delete only removes a single piece of content.
+//
+// Content that is pinned (see Pin) is not removed; instead, Delete returns a memdigest.Pinned error.
+//
+// Example
+//
+//	var mem *memdigest.SHA1
+//	
+//	// ...
+//	
+//	removed, err := mem.Delete(digest[:])
+func (receiver *fixed[A]) Delete(digest []byte) (bool, error) {
+	if nil == receiver {
+		return false, errNilReceiver
+	}
+
+	return receiver.storage().Delete(digest)
+}
+
+// ExportTar writes every piece of content in the store to ‘w’, as a tar archive (ex: with files named like "sha-1/d3/486ae9136e7856bc42212385ea797094475802").
+func (receiver *fixed[A]) ExportTar(w io.Writer) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().ExportTar(w)
+}
+
+// ExpVar returns the store's statistics as an expvar.Var.
+func (receiver *fixed[A]) ExpVar() expvar.Var {
+	if nil == receiver {
+		var store *Store
+		return store.ExpVar()
+	}
+
+	return receiver.storage().ExpVar()
+}
+
+// Freeze makes the store read-only. After that, Store (and everything else that changes the store) returns ErrReadOnly,
+// and readers no longer take any locks.
+//
+// A store can also be frozen when it is mounted, by giving the Mounter the "read-only" argument.
+func (receiver *fixed[A]) Freeze() {
+	if nil == receiver {
+		return
+	}
+
+	receiver.storage().Freeze()
+}
+
+// Frozen returns whether the store is read-only (see Freeze).
+func (receiver *fixed[A]) Frozen() bool {
+	if nil == receiver {
+		return false
+	}
+
+	return receiver.storage().Frozen()
+}
+
+// ImportTar reads a tar archive (as written by ExportTar) from ‘r’, and stores every piece of content in it.
+// If any file does not match its name, ImportTar returns an error, and stores nothing.
+func (receiver *fixed[A]) ImportTar(r io.Reader) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	return receiver.storage().ImportTar(r)
+}
+
+// Load returns the content whose digest is ‘digest’, if it is stored.
+func (receiver *fixed[A]) Load(digest []byte) (string, bool) {
+	if nil == receiver {
+		return "", false
+	}
+
+	return receiver.storage().Load(digest)
+}
+
+// Location returns the location of the content whose digest is ‘digest’, which OpenLocation accepts.
+//
+// Example
+//
+//	// location == "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/0"
+//	location, err := mem.Location(digest[:])
+func (receiver *fixed[A]) Location(digest []byte) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	return receiver.storage().Location(digest)
+}
+
+// Multihash returns the multihash of the digest ‘digest’ (see Store.Multihash).
+func (receiver *fixed[A]) Multihash(digest []byte) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	return receiver.storage().Multihash(digest)
+}
+
+// Name returns the name the store's statistics are published under, or "" if they are not published.
+func (receiver *fixed[A]) Name() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.storage().Name()
+}
+
+// Open makes the store fit the digestfs_driver.MountPoint interface.
+func (receiver *fixed[A]) Open(algorithm string, digest string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	return receiver.storage().Open(algorithm, digest)
+}
+
+// OpenLocation makes the store fit the digestfs_driver.MountPoint interface.
+func (receiver *fixed[A]) OpenLocation(location string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	return receiver.storage().OpenLocation(location)
+}
+
+// OpenLog makes the store durable, by keeping an append-only log of its content in the directory ‘directory’ (in a file named after the algorithm, ex: "sha-1.log").
+//
+// If the log already exists, then the content in it is stored (again) first, which is how the store is rebuilt after a restart.
+// After OpenLog returns, content stored with Store is appended to the log before Store returns.
+func (receiver *fixed[A]) OpenLog(directory string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().OpenLog(directory)
+}
+
+// OpenMultihash opens the content whose multihash (see Multihash) is ‘multihash’.
+func (receiver *fixed[A]) OpenMultihash(multihash []byte) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	return receiver.storage().OpenMultihash(multihash)
+}
+
+// Pin pins the content whose digest is ‘digest’, so that it is not removed by Delete, by eviction, or by expiry,
+// until it is unpinned with Unpin.
+//
+// Pins are counted. Content that was pinned N times has to be unpinned N times before it can be removed again.
+func (receiver *fixed[A]) Pin(digest []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().Pin(digest)
+}
+
+// Pins returns how many times the content whose digest is ‘digest’ is pinned.
+func (receiver *fixed[A]) Pins(digest []byte) int {
+	if nil == receiver {
+		return 0
+	}
+
+	return receiver.storage().Pins(digest)
+}
+
+// ReadFrom makes the store fit the io.ReaderFrom interface.
+//
+// ReadFrom reads an archive (written by WriteTo) from ‘r’, and stores every piece of content in it.
+// Every digest is re-verified; if the archive is corrupted, ReadFrom returns a memdigest.ArchiveError and stores nothing.
+func (receiver *fixed[A]) ReadFrom(r io.Reader) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	return receiver.storage().ReadFrom(r)
+}
+
+// SetCreateMultihash sets whether Create returns multihashes (see Multihash), rather than digests.
+//
+// This can also be set when the store is mounted, by giving the Mounter the "multihash" argument.
+func (receiver *fixed[A]) SetCreateMultihash(enabled bool) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.storage().SetCreateMultihash(enabled)
+}
+
+// SetLimits limits how much content the store holds, evicting the least recently used content when a limit is gone over.
+//
+// ‘maxBytes’ limits the total length of the stored content, and ‘maxEntries’ limits how many pieces of content are stored.
+// A limit of zero (or less) means no limit.
+//
+// Example
+//
+//	var mem memdigest.SHA1
+//	
+//	mem.SetLimits(64 * 1024 * 1024, 0) // 64 MiB
+func (receiver *fixed[A]) SetLimits(maxBytes int64, maxEntries int) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.storage().SetLimits(maxBytes, maxEntries)
+}
+
+// SetName sets the name the store's statistics are published under (via expvar, and via MetricsHandler).
+//
+// If several stores are in the same process, giving each a different name lets them be told apart.
+// If another store already has the name, then SetName returns an error.
+func (receiver *fixed[A]) SetName(name string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().SetName(name)
+}
+
+// Snapshot returns an immutable, point-in-time view of the store, which can be mounted (read-only) as "memdigest.Snapshot".
+//
+// Taking a snapshot is cheap. The store keeps accepting Store, Delete, etc, while the snapshot keeps serving what was stored when it was taken.
+func (receiver *fixed[A]) Snapshot() *Snapshot {
+	if nil == receiver {
+		return nil
+	}
+
+	return receiver.storage().Snapshot()
+}
+
+// StartJanitor starts a goroutine that removes expired content every ‘interval’.
+//
+// The janitor is stopped by StopJanitor, or by Unmount.
+func (receiver *fixed[A]) StartJanitor(interval time.Duration) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().StartJanitor(interval)
+}
+
+// StopJanitor stops the goroutine started by StartJanitor, if there is one.
+func (receiver *fixed[A]) StopJanitor() {
+	if nil == receiver {
+		return
+	}
+
+	receiver.storage().StopJanitor()
+}
+
+// Stats returns statistics about the store.
+func (receiver *fixed[A]) Stats() Stats {
+	if nil == receiver {
+		return Stats{}
+	}
+
+	return receiver.storage().Stats()
+}
+
+// Sweep removes all the content that has expired.
+func (receiver *fixed[A]) Sweep() {
+	if nil == receiver {
+		return
+	}
+
+	receiver.storage().Sweep()
+}
+
+// Unpin undoes one Pin of the content whose digest is ‘digest’.
+func (receiver *fixed[A]) Unpin(digest []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.storage().Unpin(digest)
+}
+
+// Unmount makes the store fit the digestfs_driver.MountPoint interface.
+//
+// Unmount will never return an error, but will (conceptually) remove all content it was previously storing.
+// It also stops the janitor, if one was started with StartJanitor.
+//
+// Example
+//
+// Here is an example of it being used:
+//
+//	var mem *memdigest.SHA1
+//	
+//	// ...
+//	
+//	err := mem.Unmount()
+func (receiver *fixed[A]) Unmount() error {
+	if nil == receiver {
+		return nil
+	}
+
+	return receiver.storage().Unmount()
+}
+
+// WriteTo makes the store fit the io.WriterTo interface.
+//
+// WriteTo writes every piece of content in the store to ‘w’, as an archive that ReadFrom can read back.
+func (receiver *fixed[A]) WriteTo(w io.Writer) (int64, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+
+	return receiver.storage().WriteTo(w)
+}
+
+// Writer returns a new *memdigest.Writer, which stores into the store whatever is written to it.
+//
+// Once the Writer is closed, its Digest method returns the digest of what was written.
+func (receiver *fixed[A]) Writer() *Writer {
+	if nil == receiver {
+		var store *Store
+		return store.Writer()
+	}
+
+	return receiver.storage().Writer()
+}
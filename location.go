@@ -0,0 +1,180 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsedLocation is what parseLocation returns.
+type parsedLocation struct {
+	algorithm string // lowercase (ex: "sha-1")
+	digestHexadecimal string // might be abbreviated (see Resolve), and so might be shorter than a whole digest, or an odd length
+	offset int64 // the byte offset, into the content, that the location starts at
+	end int64 // the byte offset, into the content, that the location ends at (exclusive), or -1 if it goes to the end of the content
+}
+
+// parseLocation parses a location of the form:
+//
+//	"memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/0"
+//
+// Or of the form (see parseMultihashLocation):
+//
+//	"memdigest:multihash(zQmbHQieckNGj2KwBhpzkGSLDgezGnArL6eeuvb87YLX665)/0"
+//
+// Or an RFC 6920 named information URI (see parseNamedInformation).
+//
+// The trailing number (ex: the "0" in "/0") is a byte offset into the content. The location is of the content starting at that offset.
+// (So "/0" is the whole content, and, for example, "/6" is all but the first 6 bytes of it.)
+//
+// The offset can be followed by a "-" and a second byte offset, which is where the location ends (exclusive);
+// so that, for example, "/0-5" is the first 5 bytes of the content, and "/6-11" is the 5 bytes after the first 6.
+//
+// Offsets are written in decimal, without any leading zeros (other than for "0" itself).
+// Named information URIs do not have offsets, and so are always of the whole content.
+func parseLocation(location string) (parsedLocation, bool) {
+	const prefix string = "memdigest:"
+	const infix string = ":hexadecimal("
+	const suffix string = ")/"
+
+	var parsed parsedLocation
+	parsed.end = -1
+
+	if strings.HasPrefix(location, namedInformationScheme) {
+		algorithm, digestHexadecimal, ok := parseNamedInformation(location)
+		if !ok {
+			return parsedLocation{}, false
+		}
+
+		parsed.algorithm = algorithm
+		parsed.digestHexadecimal = digestHexadecimal
+
+		return parsed, true
+	}
+
+	if !strings.HasPrefix(location, prefix) {
+		return parsedLocation{}, false
+	}
+
+	index := strings.LastIndex(location, suffix)
+	if index < len(prefix) {
+		return parsedLocation{}, false
+	}
+
+	{
+		s := location[index+len(suffix):]
+
+		offset, end, hasEnd := strings.Cut(s, "-")
+
+		var ok bool
+
+		parsed.offset, ok = parseOffset(offset)
+		if !ok {
+			return parsedLocation{}, false
+		}
+
+		if hasEnd {
+			parsed.end, ok = parseOffset(end)
+			if !ok {
+				return parsedLocation{}, false
+			}
+			if parsed.end < parsed.offset {
+				return parsedLocation{}, false
+			}
+		}
+	}
+
+	s := location[len(prefix):index]
+
+	if strings.HasPrefix(s, "multihash(") {
+		algorithm, digestHexadecimal, ok := parseMultihashLocation(s + ")")
+		if !ok {
+			return parsedLocation{}, false
+		}
+
+		parsed.algorithm = algorithm
+		parsed.digestHexadecimal = digestHexadecimal
+
+		return parsed, true
+	}
+
+	index = strings.Index(s, infix)
+	if index < 0 {
+		return parsedLocation{}, false
+	}
+	parsed.algorithm = s[:index]
+	parsed.digestHexadecimal = s[index+len(infix):]
+
+	if _, valid := parseHexPrefix(parsed.digestHexadecimal); !valid {
+		return parsedLocation{}, false
+	}
+
+	return parsed, true
+}
+
+// locationContent returns the part of the content ‘value’ that the (parsed) location ‘location’ is of.
+//
+// The part is a substring of ‘value’, and so the content is not copied.
+// If the location's range does not fit within the content, then a digestfs_driver.BadLocation error is returned.
+func locationContent(location string, value string, parsed parsedLocation) (digestfs_driver.Content, error) {
+	end := int64(len(value))
+	if 0 <= parsed.end {
+		end = parsed.end
+	}
+
+	if int64(len(value)) < end || end < parsed.offset {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	return newContent(value[parsed.offset:end]), nil
+}
+
+// parseOffset parses a (decimal, non-negative, and without leading zeros) byte offset.
+func parseOffset(s string) (int64, bool) {
+	if "" == s {
+		return 0, false
+	}
+	if 1 < len(s) && '0' == s[0] {
+		return 0, false
+	}
+	for _, r := range s {
+		if r < '0' || '9' < r {
+			return 0, false
+		}
+	}
+
+	offset, err := strconv.ParseInt(s, 10, 64)
+	if nil != err {
+		return 0, false
+	}
+
+	return offset, true
+}
+
+// Location returns the location of the content whose digest is ‘digest’, which OpenLocation accepts.
+//
+// Location is the inverse of OpenLocation. It returns a location of the form:
+//
+//	"memdigest:sha-256:hexadecimal(c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0"
+//
+// Where "sha-256" is the (lowercase) name of the store's algorithm, and the trailing "0" is the byte offset,
+// into the content, that the location starts at. (Location always returns the location of the whole content.)
+//
+// ‘digest’ is in binary form, not hexadecimal. The content does not need to be stored.
+//
+// Example
+//
+//	location, err := mem.Location(digest)
+func (receiver *Store) Location(digest []byte) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	if receiver.size != len(digest) {
+		return "", fmt.Errorf("memdigest: Wrong Digest Size: expected %d, but actually got %d", receiver.size, len(digest))
+	}
+
+	return fmt.Sprintf("memdigest:%s:hexadecimal(%x)/0", strings.ToLower(receiver.algorithm), digest), nil
+}
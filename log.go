@@ -0,0 +1,423 @@
+package memdigest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// The log file, written by a Store after OpenLog is called, is (with all integers big-endian):
+//
+//	magic      8 bytes: "MEMDGLOG"
+//	version    1 byte:  0x01
+//	name size  1 byte:  the length of the algorithm name
+//	name       the algorithm name (ex: "SHA-1")
+//	size       2 bytes: the length of each digest
+//
+// Followed by any number of records, each of which is:
+//
+//	kind       1 byte: '+' if the content was stored, or '-' if it was deleted
+//	digest     ‘size’ bytes: the digest of the content, in binary form
+//	length     8 bytes: the length of the content (zero, for '-')
+//	content    ‘length’ bytes: the content
+//	checksum   4 bytes: the CRC-32 (IEEE) of all of the above fields of the record
+//
+// A record that was only partly written (because of a crash, for example) fails its checksum (or is cut short),
+// and so it, and anything after it, is truncated from the log the next time it is opened.
+const (
+	logMagic string = "MEMDGLOG"
+	logVersion byte = 0x01
+
+	logPut byte = '+'
+	logDelete byte = '-'
+)
+
+// journal is the append-only log that a Store writes its content to, when it is durable (see OpenLog).
+//
+// When both the journal's lock and a shard's lock are held, the journal's lock is always taken first.
+type journal struct {
+	enabled atomic.Bool
+
+	mutex sync.Mutex
+	path string
+	file *os.File
+	size int64 // the length of the log file, up to the end of its last whole record
+}
+
+func (receiver *journal) isEnabled() bool {
+	return receiver.enabled.Load()
+}
+
+// put appends to the log that ‘value’ was stored under ‘key’, unless ‘e’ (what is already stored under ‘key’) shows it was already appended.
+//
+// The caller must hold the lock.
+func (receiver *journal) put(key string, value string, e *entry) error {
+	if nil == receiver.file {
+		return nil
+	}
+
+	// Content that never expires was already appended when it was stored.
+	if nil != e && !e.expiring() {
+		return nil
+	}
+
+	return receiver.append(logRecord(logPut, key, value))
+}
+
+// remove appends to the log that whatever was stored under ‘key’ was deleted.
+//
+// The caller must hold the lock.
+func (receiver *journal) remove(key string) error {
+	if nil == receiver.file {
+		return nil
+	}
+
+	return receiver.append(logRecord(logDelete, key, ""))
+}
+
+// append appends ‘record’ to the log, and waits for it to reach the disk.
+//
+// The caller must hold the lock.
+func (receiver *journal) append(record []byte) error {
+	if _, err := receiver.file.Write(record); nil != err {
+		// Do not leave a partly written record in the log.
+		receiver.file.Truncate(receiver.size)
+		receiver.file.Seek(receiver.size, io.SeekStart)
+		return err
+	}
+
+	if err := receiver.file.Sync(); nil != err {
+		return err
+	}
+
+	receiver.size += int64(len(record))
+
+	return nil
+}
+
+// close stops the log (if it was started).
+func (receiver *journal) close() error {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if nil == receiver.file {
+		return nil
+	}
+
+	receiver.enabled.Store(false)
+
+	err := receiver.file.Close()
+
+	receiver.file = nil
+	receiver.path = ""
+	receiver.size = 0
+
+	return err
+}
+
+// logRecord returns the record (see the format, above) for ‘kind’, ‘key’, and ‘value’.
+func logRecord(kind byte, key string, value string) []byte {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte(kind)
+	buffer.WriteString(key)
+	binary.Write(&buffer, binary.BigEndian, uint64(len(value)))
+	buffer.WriteString(value)
+	binary.Write(&buffer, binary.BigEndian, crc32.ChecksumIEEE(buffer.Bytes()))
+
+	return buffer.Bytes()
+}
+
+func (receiver *Store) logHeader() []byte {
+	var header bytes.Buffer
+
+	header.WriteString(logMagic)
+	header.WriteByte(logVersion)
+	header.WriteByte(byte(len(receiver.algorithm)))
+	header.WriteString(receiver.algorithm)
+	binary.Write(&header, binary.BigEndian, uint16(receiver.size))
+
+	return header.Bytes()
+}
+
+// OpenLog makes the store durable, by keeping an append-only log of its content in the directory ‘directory’.
+//
+// The log file is named after the (lowercase) algorithm (ex: "sha-1.log"). If it already exists, then
+// the content in it is stored (again) first, which is how the store is rebuilt after a restart.
+// If the log ends with a record that was only partly written (because of a crash, for example), then that record is truncated from the log.
+//
+// After OpenLog returns, content stored with Store, Insert, StoreFrom, StoreFromN, or a Writer is appended to the log (and synced to disk)
+// before it is stored in memory; and content removed with Delete is recorded as deleted in the log.
+// If appending to the log fails, then the error is returned, and the content is not stored.
+//
+// Content stored with StoreWithTTL is never appended to the log, since it is temporary.
+// Nor is content that is evicted (see SetLimits), swept (see Sweep), or collected (see Collect) recorded as deleted in the log.
+// Use CompactLog to rewrite the log to match what is stored in memory.
+//
+// OpenLog should be called before the store is used.
+//
+// Example
+//
+//	var mem memdigest.SHA1
+//
+//	err := mem.OpenLog("/var/lib/myapp/blobs")
+func (receiver *Store) OpenLog(directory string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if receiver.frozen.Load() {
+		return ErrReadOnly
+	}
+
+	if 255 < len(receiver.algorithm) {
+		return fmt.Errorf("memdigest: Algorithm Name Too Long: %q", receiver.algorithm)
+	}
+
+	receiver.journal.mutex.Lock()
+	defer receiver.journal.mutex.Unlock()
+
+	if nil != receiver.journal.file {
+		return errLogOpen
+	}
+
+	path := filepath.Join(directory, receiver.tarDirectory()+".log")
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if nil != err {
+		return err
+	}
+
+	contents, size, err := receiver.replay(file)
+	if nil != err {
+		file.Close()
+		return err
+	}
+
+	if err := file.Truncate(size); nil != err {
+		file.Close()
+		return err
+	}
+	if _, err := file.Seek(size, io.SeekStart); nil != err {
+		file.Close()
+		return err
+	}
+
+	if 0 == size {
+		header := receiver.logHeader()
+
+		if _, err := file.Write(header); nil != err {
+			file.Close()
+			return err
+		}
+		if err := file.Sync(); nil != err {
+			file.Close()
+			return err
+		}
+
+		size = int64(len(header))
+	}
+
+	// The journal is not enabled yet, so this does not append anything to the log.
+	for key, value := range contents {
+		receiver.insert(key, value, 0)
+	}
+
+	receiver.journal.path = path
+	receiver.journal.file = file
+	receiver.journal.size = size
+	receiver.journal.enabled.Store(true)
+
+	return nil
+}
+
+// replay reads the log file ‘file’, and returns what content it says is stored, and the length of the log up to the end of its last whole record.
+//
+// A log whose header is cut short is treated as empty.
+func (receiver *Store) replay(file *os.File) (map[string]string, int64, error) {
+	info, err := file.Stat()
+	if nil != err {
+		return nil, 0, err
+	}
+	fileSize := info.Size()
+
+	counter := countingReader{reader:bufio.NewReader(file)}
+
+	{
+		header := receiver.logHeader()
+
+		p := make([]byte, len(header))
+		if _, err := io.ReadFull(&counter, p); nil != err {
+			return nil, 0, nil
+		}
+		if !bytes.Equal(header, p) {
+			return nil, 0, fmt.Errorf("memdigest: Bad Log: %q is not a version %d log for %s", file.Name(), logVersion, receiver.algorithm)
+		}
+	}
+
+	contents := map[string]string{}
+
+	for {
+		good := counter.n
+
+		head := make([]byte, 1+receiver.size+8)
+		if _, err := io.ReadFull(&counter, head); nil != err {
+			return contents, good, nil
+		}
+
+		kind := head[0]
+		key := string(head[1:1+receiver.size])
+		length := binary.BigEndian.Uint64(head[1+receiver.size:])
+
+		if logPut != kind && logDelete != kind {
+			return contents, good, nil
+		}
+		if uint64(fileSize - counter.n) < length {
+			return contents, good, nil
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(&counter, value); nil != err {
+			return contents, good, nil
+		}
+
+		var checksum uint32
+		if err := binary.Read(&counter, binary.BigEndian, &checksum); nil != err {
+			return contents, good, nil
+		}
+		if crc32.Update(crc32.ChecksumIEEE(head), crc32.IEEETable, value) != checksum {
+			return contents, good, nil
+		}
+
+		switch kind {
+		case logPut:
+			contents[key] = string(value)
+		case logDelete:
+			delete(contents, key)
+		}
+	}
+}
+
+// CompactLog rewrites the log (see OpenLog) so that it only has the content that is currently stored,
+// leaving out content that was deleted, evicted, swept, or collected, and duplicate records.
+//
+// The new log is written to a temporary file, which then replaces the old log, so that a crash during
+// CompactLog leaves either the old log or the new log, but never a mix.
+//
+// While CompactLog runs, storing and deleting (durable) content waits for it.
+func (receiver *Store) CompactLog() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.journal.mutex.Lock()
+	defer receiver.journal.mutex.Unlock()
+
+	if nil == receiver.journal.file {
+		return errLogNotOpen
+	}
+
+	path := receiver.journal.path
+	directory := filepath.Dir(path)
+
+	temporary, err := os.CreateTemp(directory, filepath.Base(path)+".*.tmp")
+	if nil != err {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+
+	size, err := receiver.writeLog(temporary)
+	if nil == err {
+		err = temporary.Sync()
+	}
+	if closeErr := temporary.Close(); nil == err {
+		err = closeErr
+	}
+	if nil != err {
+		return err
+	}
+
+	if err := os.Rename(temporary.Name(), path); nil != err {
+		return err
+	}
+	syncDirectory(directory)
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if nil != err {
+		return err
+	}
+	if _, err := file.Seek(size, io.SeekStart); nil != err {
+		file.Close()
+		return err
+	}
+
+	receiver.journal.file.Close()
+	receiver.journal.file = file
+	receiver.journal.size = size
+
+	return nil
+}
+
+// writeLog writes a log header, and a record for every piece of content that never expires, to ‘w’, and returns how many bytes it wrote.
+func (receiver *Store) writeLog(w io.Writer) (int64, error) {
+	counter := countingWriter{writer:w}
+	bw := bufio.NewWriter(&counter)
+
+	if _, err := bw.Write(receiver.logHeader()); nil != err {
+		return counter.n, err
+	}
+
+	for i := range receiver.shards {
+		shard := &receiver.shards[i]
+
+		var items []item
+		{
+			shard.mutex.RLock()
+			for key, e := range shard.data {
+				if e.expiring() {
+					continue
+				}
+				items = append(items, item{key:key, value:e.value})
+			}
+			shard.mutex.RUnlock()
+		}
+
+		for _, item := range items {
+			if _, err := bw.Write(logRecord(logPut, item.key, item.value)); nil != err {
+				return counter.n, err
+			}
+		}
+	}
+
+	err := bw.Flush()
+
+	return counter.n, err
+}
+
+// syncDirectory syncs the directory ‘directory’, so that a file renamed into it is durable. (Not every platform supports this, so errors are ignored.)
+func syncDirectory(directory string) {
+	dir, err := os.Open(directory)
+	if nil != err {
+		return
+	}
+	defer dir.Close()
+
+	dir.Sync()
+}
+
+// CloseLog stops the store from being durable (see OpenLog), and closes the log.
+//
+// The content stays stored in memory.
+func (receiver *Store) CloseLog() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	return receiver.journal.close()
+}
@@ -0,0 +1,223 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+
+	"testing"
+)
+
+func TestSHA1OpenLog(t *testing.T) {
+
+	directory := t.TempDir()
+
+	var digestApple [sha1.Size]byte
+	var digestBanana [sha1.Size]byte
+	{
+		var mem memdigest.SHA1
+
+		if err := mem.OpenLog(directory); nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+
+		digestApple, _ = mem.Store([]byte("apple"))
+		digestBanana, _ = mem.Store([]byte("BANANA"))
+		mem.Store([]byte("apple"))
+		mem.Store([]byte("cherry"))
+
+		{
+			digest := sha1.Sum([]byte("cherry"))
+			if _, err := mem.Delete(digest[:]); nil != err {
+				t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+			}
+		}
+
+		if err := mem.CloseLog(); nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+	}
+
+	var mem memdigest.SHA1
+	if err := mem.OpenLog(directory); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer mem.CloseLog()
+
+	tests := []struct{
+		Digest [sha1.Size]byte
+		ExpectedContent string
+		ExpectedFound bool
+	}{
+		{
+			Digest: digestApple,
+			ExpectedContent: "apple",
+			ExpectedFound: true,
+		},
+		{
+			Digest: digestBanana,
+			ExpectedContent: "BANANA",
+			ExpectedFound: true,
+		},
+		{
+			Digest: sha1.Sum([]byte("cherry")),
+			ExpectedContent: "",
+			ExpectedFound: false,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		actualContent, actualFound := mem.Load(test.Digest[:])
+
+		if expected, actual := test.ExpectedFound, actualFound; expected != actual {
+			t.Errorf("For test #%d, whether the content was found after replaying the log was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %t", expected)
+			t.Logf("ACTUAL:   %t", actual)
+			continue
+		}
+
+		if expected, actual := test.ExpectedContent, actualContent; expected != actual {
+			t.Errorf("For test #%d, the content after replaying the log was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func TestSHA1OpenLogTornWrite(t *testing.T) {
+
+	directory := t.TempDir()
+	path := filepath.Join(directory, "sha-1.log")
+
+	var sizeAfterApple int64
+	{
+		var mem memdigest.SHA1
+
+		if err := mem.OpenLog(directory); nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+
+		mem.Store([]byte("apple"))
+
+		info, err := os.Stat(path)
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+		sizeAfterApple = info.Size()
+
+		mem.Store([]byte("BANANA"))
+		mem.CloseLog()
+	}
+
+	// Simulate a crash in the middle of appending the record for "BANANA".
+	if err := os.Truncate(path, sizeAfterApple+10); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	var mem memdigest.SHA1
+	if err := mem.OpenLog(directory); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer mem.CloseLog()
+
+	{
+		info, err := os.Stat(path)
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+
+		if expected, actual := sizeAfterApple, info.Size(); expected != actual {
+			t.Errorf("The size of the log after it was truncated was not what was expected.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+	}
+
+	digestApple := sha1.Sum([]byte("apple"))
+	digestBanana := sha1.Sum([]byte("BANANA"))
+
+	if _, found := mem.Load(digestApple[:]); !found {
+		t.Errorf("Expected the content from before the torn write to exist, but it actually didn't.")
+	}
+	if _, found := mem.Load(digestBanana[:]); found {
+		t.Errorf("Did not expect the content from the torn write to exist, but it actually did.")
+	}
+
+	// Appending after the truncation should work.
+	mem.Store([]byte("cherry"))
+	mem.CloseLog()
+
+	var again memdigest.SHA1
+	if err := again.OpenLog(directory); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer again.CloseLog()
+
+	if expected, actual := 2, again.Stats().Blobs; expected != actual {
+		t.Errorf("The number of blobs after replaying the log was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
+
+func TestSHA1CompactLog(t *testing.T) {
+
+	directory := t.TempDir()
+	path := filepath.Join(directory, "sha-1.log")
+
+	var mem memdigest.SHA1
+	if err := mem.OpenLog(directory); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	mem.Store([]byte("apple"))
+	for i := 0; i < 10; i++ {
+		digest, _ := mem.Store([]byte("BANANA BANANA BANANA BANANA BANANA BANANA"))
+		mem.Delete(digest[:])
+	}
+
+	before, err := os.Stat(path)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if err := mem.CompactLog(); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	after, err := os.Stat(path)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if !(after.Size() < before.Size()) {
+		t.Errorf("Expected the log to be smaller after compaction, but it actually wasn't.")
+		t.Logf("BEFORE: %d", before.Size())
+		t.Logf("AFTER:  %d", after.Size())
+	}
+
+	// Appending after compaction should work.
+	mem.Store([]byte("cherry"))
+	mem.CloseLog()
+
+	entries, _ := os.ReadDir(directory)
+	if expected, actual := 1, len(entries); expected != actual {
+		t.Errorf("Expected only the log file to be left in the directory, but actually there were %d file(s).", actual)
+	}
+
+	var again memdigest.SHA1
+	if err := again.OpenLog(directory); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer again.CloseLog()
+
+	if expected, actual := 2, again.Stats().Blobs; expected != actual {
+		t.Errorf("The number of blobs after replaying the compacted log was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
@@ -0,0 +1,251 @@
+package memdigest
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// lru keeps track of the order in which the content of a Store was used, so that when the Store
+// goes over its limits the least recently used content can be evicted.
+//
+// An lru only keeps track of anything once it has been enabled (by setting a limit), so that a
+// Store without limits does not pay for it.
+//
+// When both a shard's lock and an lru's lock are held, the shard's lock is always taken first.
+type lru struct {
+	enabled atomic.Bool
+
+	mutex sync.Mutex
+	maxBytes int64
+	maxEntries int
+	onEvict func(digest string, size int)
+	list list.List // front is most recently used
+	elements map[string]*list.Element
+	bytes int64
+}
+
+type lruEntry struct {
+	key string
+	size int
+	pinned bool // pinned content is never a victim
+}
+
+func (receiver *lru) isEnabled() bool {
+	return receiver.enabled.Load()
+}
+
+// add starts keeping track of ‘key’, as the most recently used.
+func (receiver *lru) add(key string, size int) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if nil == receiver.elements {
+		receiver.elements = map[string]*list.Element{}
+	}
+
+	if element, found := receiver.elements[key]; found {
+		receiver.list.MoveToFront(element)
+		return
+	}
+
+	receiver.elements[key] = receiver.list.PushFront(&lruEntry{key:key, size:size})
+	receiver.bytes += int64(size)
+}
+
+// touch marks ‘key’ as the most recently used.
+func (receiver *lru) touch(key string) {
+	if !receiver.isEnabled() {
+		return
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	element, found := receiver.elements[key]
+	if !found {
+		return
+	}
+
+	receiver.list.MoveToFront(element)
+}
+
+// remove stops keeping track of ‘key’.
+func (receiver *lru) remove(key string) {
+	if !receiver.isEnabled() {
+		return
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	element, found := receiver.elements[key]
+	if !found {
+		return
+	}
+
+	receiver.list.Remove(element)
+	delete(receiver.elements, key)
+	receiver.bytes -= int64(element.Value.(*lruEntry).size)
+}
+
+// pin marks whether ‘key’ is pinned.
+func (receiver *lru) pin(key string, pinned bool) {
+	if !receiver.isEnabled() {
+		return
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	element, found := receiver.elements[key]
+	if !found {
+		return
+	}
+
+	element.Value.(*lruEntry).pinned = pinned
+}
+
+// victims stops keeping track of, and returns, the least recently used entries that have to be evicted
+// to get back within the limits.
+//
+// Pinned entries are never victims. So, if enough content is pinned, the limits might still be gone over.
+func (receiver *lru) victims() []lruEntry {
+	if !receiver.isEnabled() {
+		return nil
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	var entries []lruEntry
+
+	for element := receiver.list.Back(); nil != element && receiver.over(); {
+		entry := element.Value.(*lruEntry)
+		previous := element.Prev()
+
+		if !entry.pinned {
+			receiver.list.Remove(element)
+			delete(receiver.elements, entry.key)
+			receiver.bytes -= int64(entry.size)
+
+			entries = append(entries, *entry)
+		}
+
+		element = previous
+	}
+
+	return entries
+}
+
+// over returns whether the limits have been gone over.
+//
+// The caller must hold the lock.
+func (receiver *lru) over() bool {
+	if 0 < receiver.maxBytes && receiver.maxBytes < receiver.bytes {
+		return true
+	}
+	if 0 < receiver.maxEntries && receiver.maxEntries < receiver.list.Len() {
+		return true
+	}
+
+	return false
+}
+
+// reset stops keeping track of everything (but keeps the limits).
+func (receiver *lru) reset() {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	receiver.list.Init()
+	receiver.elements = nil
+	receiver.bytes = 0
+}
+
+func (receiver *lru) evicted() func(digest string, size int) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.onEvict
+}
+
+// SetLimits limits how much content the store holds.
+//
+// ‘maxBytes’ limits the total length of the stored content, and ‘maxEntries’ limits how many pieces of content are stored.
+// A limit of zero (or less) means no limit.
+//
+// When storing content would go over a limit, the least recently used content is evicted until the store
+// is back within its limits. Store, Load, Open, and OpenLocation all count as uses.
+// (Content that is, by itself, larger than ‘maxBytes’ is evicted as soon as it is stored.)
+//
+// Content that is pinned (see Pin) is never evicted, even if that means the store stays over its limits.
+func (receiver *Store) SetLimits(maxBytes int64, maxEntries int) {
+	if nil == receiver {
+		return
+	}
+
+	{
+		receiver.lru.mutex.Lock()
+
+		receiver.lru.maxBytes = maxBytes
+		receiver.lru.maxEntries = maxEntries
+
+		receiver.lru.enabled.Store(true)
+
+		receiver.lru.mutex.Unlock()
+	}
+
+	// Start keeping track of any content that was stored before there were limits.
+	for i := range receiver.shards {
+		shard := &receiver.shards[i]
+
+		shard.mutex.RLock()
+		for key, e := range shard.data {
+			receiver.lru.add(key, len(e.value))
+			if 0 < e.pins {
+				receiver.lru.pin(key, true)
+			}
+		}
+		shard.mutex.RUnlock()
+	}
+
+	receiver.evict()
+}
+
+// OnEvict sets the function that is called (with the digest and length of the content) whenever content is evicted.
+//
+// The digest is in binary form, not hexadecimal.
+func (receiver *Store) OnEvict(fn func(digest string, size int)) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.lru.mutex.Lock()
+	defer receiver.lru.mutex.Unlock()
+
+	receiver.lru.onEvict = fn
+}
+
+// evict evicts the least recently used content until the store is back within its limits.
+func (receiver *Store) evict() {
+	if receiver.frozen.Load() {
+		return
+	}
+
+	victims := receiver.lru.victims()
+	if len(victims) < 1 {
+		return
+	}
+
+	fn := receiver.lru.evicted()
+
+	for _, victim := range victims {
+		if !receiver.shard(victim.key).evict(victim.key, &receiver.lru) {
+			continue
+		}
+
+		if nil != fn {
+			fn(victim.key, victim.size)
+		}
+	}
+}
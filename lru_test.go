@@ -0,0 +1,109 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+
+	"testing"
+)
+
+func TestSHA1SetLimitsMaxBytes(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	var evicted []string
+	mem.OnEvict(func(digest [sha1.Size]byte, size int) {
+		value := map[[sha1.Size]byte]string{
+			sha1.Sum([]byte("apple")):  "apple",
+			sha1.Sum([]byte("BANANA")): "BANANA",
+			sha1.Sum([]byte("Cherry")): "Cherry",
+			sha1.Sum([]byte("dATE")):   "dATE",
+		}[digest]
+
+		if expected, actual := len(value), size; expected != actual {
+			t.Errorf("The size given to the eviction callback was not what was expected.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+
+		evicted = append(evicted, value)
+	})
+
+	// "apple" + "BANANA" == 11 bytes.
+	mem.SetLimits(11, 0)
+
+	apple, _ := mem.Store([]byte("apple"))
+	banana, _ := mem.Store([]byte("BANANA"))
+
+	if 0 != len(evicted) {
+		t.Fatalf("Did not expect anything to be evicted yet, but actually got: %q", evicted)
+	}
+
+	// Using "apple" makes "BANANA" the least recently used.
+	if _, found := mem.Load(apple[:]); !found {
+		t.Fatalf("Expected \"apple\" to be stored, but it actually wasn't.")
+	}
+
+	cherry, _ := mem.Store([]byte("Cherry"))
+
+	if expected, actual := 1, len(evicted); expected != actual {
+		t.Fatalf("Expected %d eviction, but actually got %d: %q", expected, actual, evicted)
+	}
+	if expected, actual := "BANANA", evicted[0]; expected != actual {
+		t.Errorf("The content that was evicted was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	if _, found := mem.Load(banana[:]); found {
+		t.Errorf("Did not expect evicted content to still exist, but it actually did.")
+	}
+	if _, found := mem.Load(apple[:]); !found {
+		t.Errorf("Expected \"apple\" to still be stored, but it actually wasn't.")
+	}
+	if _, found := mem.Load(cherry[:]); !found {
+		t.Errorf("Expected \"Cherry\" to still be stored, but it actually wasn't.")
+	}
+}
+
+func TestSHA1SetLimitsMaxEntries(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	// Content stored before the limits are set is also subject to them.
+	apple, _ := mem.Store([]byte("apple"))
+	banana, _ := mem.Store([]byte("BANANA"))
+	cherry, _ := mem.Store([]byte("Cherry"))
+
+	mem.SetLimits(0, 2)
+
+	var count int
+	for _, digest := range [][sha1.Size]byte{apple, banana, cherry} {
+		if _, found := mem.Load(digest[:]); found {
+			count++
+		}
+	}
+
+	if expected, actual := 2, count; expected != actual {
+		t.Errorf("The number of pieces of content that are stored was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	mem.Store([]byte("dATE"))
+
+	count = 0
+	for _, content := range []string{"apple", "BANANA", "Cherry", "dATE"} {
+		digest := sha1.Sum([]byte(content))
+		if _, found := mem.Load(digest[:]); found {
+			count++
+		}
+	}
+
+	if expected, actual := 2, count; expected != actual {
+		t.Errorf("The number of pieces of content that are stored was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
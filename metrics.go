@@ -0,0 +1,222 @@
+package memdigest
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+func init() {
+	expvar.Publish("memdigest", expvar.Func(func() any {
+		return published.stats()
+	}))
+}
+
+// published are the stores that have a name, and so whose statistics are published (via expvar, and via MetricsHandler).
+var published registry
+
+type registry struct {
+	mutex sync.RWMutex
+	stores map[string]*Store
+}
+
+// add adds ‘store’ under the name ‘name’, and returns whether it did; it does not if another store already has that name.
+func (receiver *registry) add(name string, store *Store) bool {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return receiver.addLocked(name, store)
+}
+
+// addUnique adds ‘store’ under the name ‘name’, or, if another store already has that name,
+// under the first of "‘name’#2", "‘name’#3", ... that no other store has; and returns the name it was added under.
+func (receiver *registry) addUnique(name string, store *Store) string {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	candidate := name
+	for n := 2; !receiver.addLocked(candidate, store); n++ {
+		candidate = fmt.Sprintf("%s#%d", name, n)
+	}
+
+	return candidate
+}
+
+// The caller must hold the (write) lock.
+func (receiver *registry) addLocked(name string, store *Store) bool {
+	if nil == receiver.stores {
+		receiver.stores = map[string]*Store{}
+	}
+
+	if existing, found := receiver.stores[name]; found && store != existing {
+		return false
+	}
+
+	receiver.stores[name] = store
+
+	return true
+}
+
+// remove removes the store named ‘name’, if it is ‘store’.
+func (receiver *registry) remove(name string, store *Store) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if store == receiver.stores[name] {
+		delete(receiver.stores, name)
+	}
+}
+
+func (receiver *registry) list() []*Store {
+	var stores []*Store
+	{
+		receiver.mutex.RLock()
+		for _, store := range receiver.stores {
+			stores = append(stores, store)
+		}
+		receiver.mutex.RUnlock()
+	}
+
+	// (This happens after the lock is released, since Name takes the store's lock, and SetName takes the store's lock before this lock.)
+	sort.Slice(stores, func(i, j int) bool {
+		return stores[i].Name() < stores[j].Name()
+	})
+
+	return stores
+}
+
+func (receiver *registry) stats() map[string]Stats {
+	var m = map[string]Stats{}
+
+	for _, store := range receiver.list() {
+		m[store.Name()] = store.Stats()
+	}
+
+	return m
+}
+
+// Name returns the name the store's statistics are published under, or "" if they are not published.
+//
+// When a store is mounted with digestfs, and does not already have a name, it is named after the digestfs mount name (ex: "memdigest.SHA1").
+// If another store already has that name, then a number is added to it, to make it unique (ex: "memdigest.SHA1#2").
+func (receiver *Store) Name() string {
+	if nil == receiver {
+		return ""
+	}
+
+	receiver.nameMutex.Lock()
+	defer receiver.nameMutex.Unlock()
+
+	return receiver.name
+}
+
+// SetName sets the name the store's statistics are published under.
+//
+// The statistics are published via expvar (under "memdigest"), and via MetricsHandler.
+// If several stores are in the same process, giving each a different name lets them be told apart.
+// If another store already has the name, then SetName returns an error, and the store keeps the name it had.
+// An empty name stops the store's statistics from being published.
+func (receiver *Store) SetName(name string) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	receiver.nameMutex.Lock()
+	defer receiver.nameMutex.Unlock()
+
+	if name == receiver.name {
+		return nil
+	}
+
+	if "" != name && !published.add(name, receiver) {
+		return fmt.Errorf("memdigest: Name Taken: another store is already named %q", name)
+	}
+
+	if "" != receiver.name {
+		published.remove(receiver.name, receiver)
+	}
+
+	receiver.name = name
+
+	return nil
+}
+
+// mounted names the store after the digestfs mount name ‘name’ (made unique, if another store already has it), unless it already has a name.
+func (receiver *Store) mounted(name string) {
+	receiver.nameMutex.Lock()
+	defer receiver.nameMutex.Unlock()
+
+	if "" != receiver.name {
+		return
+	}
+
+	receiver.name = published.addUnique(name, receiver)
+}
+
+// ExpVar returns the store's statistics as an expvar.Var.
+//
+// Example
+//
+//	expvar.Publish("assets", mem.ExpVar())
+func (receiver *Store) ExpVar() expvar.Var {
+	return expvar.Func(func() any {
+		return receiver.Stats()
+	})
+}
+
+// MetricsHandler returns an http.Handler that writes the statistics of every named store (see SetName),
+// in the Prometheus text exposition format.
+//
+// Each store is labelled with its name (as the "mount" label) and its algorithm (as the "algorithm" label).
+//
+// Example
+//
+//	http.Handle("/metrics", memdigest.MetricsHandler())
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		writeMetrics(w, published.list())
+	})
+}
+
+func writeMetrics(w io.Writer, stores []*Store) {
+	var stats []Stats
+	for _, store := range stores {
+		stats = append(stats, store.Stats())
+	}
+
+	metrics := []struct{
+		Name string
+		Type string
+		Help string
+		Value func(Stats) any
+	}{
+		{"memdigest_blobs", "gauge", "How many pieces of content are stored.", func(s Stats) any { return s.Blobs }},
+		{"memdigest_bytes", "gauge", "The total length of the stored content.", func(s Stats) any { return s.Bytes }},
+		{"memdigest_largest_blob_bytes", "gauge", "The length of the largest piece of stored content.", func(s Stats) any { return s.LargestBlob }},
+		{"memdigest_stores_total", "counter", "How many times content was stored (including duplicates).", func(s Stats) any { return s.Stores }},
+		{"memdigest_duplicate_stores_total", "counter", "How many times content was stored that was already stored.", func(s Stats) any { return s.DuplicateStores }},
+		{"memdigest_load_hits_total", "counter", "How many times Load or Open found the content.", func(s Stats) any { return s.LoadHits }},
+		{"memdigest_load_misses_total", "counter", "How many times Load or Open did not find the content.", func(s Stats) any { return s.LoadMisses }},
+		{"memdigest_open_location_failures_total", "counter", "How many times OpenLocation returned an error.", func(s Stats) any { return s.OpenLocationFailures }},
+	}
+
+	for _, metric := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n", metric.Name, metric.Help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", metric.Name, metric.Type)
+
+		for i, store := range stores {
+			fmt.Fprintf(w, "%s{mount=\"%s\",algorithm=\"%s\"} %v\n", metric.Name, escapeLabelValue(store.Name()), escapeLabelValue(store.Algorithm()), metric.Value(stats[i]))
+		}
+	}
+}
+
+// escapeLabelValue escapes a label value, as the Prometheus text exposition format requires.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,161 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"github.com/reiver/go-digestfs"
+
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+
+	"testing"
+)
+
+func TestSHA1MetricsHandler(t *testing.T) {
+
+	var assets memdigest.SHA1
+	assets.SetName("assets")
+	defer assets.Unmount()
+
+	var uploads memdigest.SHA1
+	uploads.SetName("up\"loads")
+	defer uploads.Unmount()
+
+	assets.Store([]byte("apple"))
+	assets.Store([]byte("apple"))
+	uploads.Store([]byte("BANANA"))
+
+	recorder := httptest.NewRecorder()
+	memdigest.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(recorder.Result().Body)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	for _, expected := range []string{
+		"# TYPE memdigest_blobs gauge\n",
+		"# TYPE memdigest_stores_total counter\n",
+		`memdigest_blobs{mount="assets",algorithm="SHA-1"} 1` + "\n",
+		`memdigest_bytes{mount="up\"loads",algorithm="SHA-1"} 6` + "\n",
+		`memdigest_stores_total{mount="assets",algorithm="SHA-1"} 2` + "\n",
+		`memdigest_duplicate_stores_total{mount="assets",algorithm="SHA-1"} 1` + "\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Expected the metrics to contain %q, but they actually didn't.", expected)
+			t.Logf("METRICS:\n%s", body)
+		}
+	}
+}
+
+func TestSHA1ExpVar(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("apple"))
+
+	var stats memdigest.Stats
+	if err := json.Unmarshal([]byte(mem.ExpVar().String()), &stats); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if expected, actual := 1, stats.Blobs; expected != actual {
+		t.Errorf("The number of blobs was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	// Mounting names the store after the mount name, and so publishes it.
+	var mountpoint digestfs.MountPoint
+	if err := mountpoint.Mount("memdigest.SHA1", &mem); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer mem.Unmount()
+
+	if expected, actual := "memdigest.SHA1", mem.Name(); expected != actual {
+		t.Errorf("The name of the mounted store was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	if !strings.Contains(expvar.Get("memdigest").String(), `"memdigest.SHA1"`) {
+		t.Errorf("Expected the mounted store to be published via expvar, but it actually wasn't: %s", expvar.Get("memdigest"))
+	}
+}
+
+func TestSHA1MetricsSeveralMounted(t *testing.T) {
+
+	var first memdigest.SHA1
+	first.Store([]byte("apple"))
+
+	var second memdigest.SHA1
+	second.Store([]byte("BANANA"))
+	second.Store([]byte("Cherry"))
+
+	var mountpoint digestfs.MountPoint
+	if err := mountpoint.Mount("memdigest.SHA1", &first); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer first.Unmount()
+
+	var other digestfs.MountPoint
+	if err := other.Mount("memdigest.SHA1", &second); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer second.Unmount()
+
+	if first.Name() == second.Name() {
+		t.Errorf("Expected the two mounted stores to have different names, but they actually didn't: %q", first.Name())
+	}
+
+	recorder := httptest.NewRecorder()
+	memdigest.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(recorder.Result().Body)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	for _, expected := range []string{
+		fmt.Sprintf(`memdigest_blobs{mount="%s",algorithm="SHA-1"} 1`, first.Name()) + "\n",
+		fmt.Sprintf(`memdigest_blobs{mount="%s",algorithm="SHA-1"} 2`, second.Name()) + "\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Expected the metrics to contain %q, but they actually didn't.", expected)
+			t.Logf("METRICS:\n%s", body)
+		}
+	}
+}
+
+func TestSHA1SetNameTaken(t *testing.T) {
+
+	var first memdigest.SHA1
+	if err := first.SetName("taken"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer first.Unmount()
+
+	var second memdigest.SHA1
+	if err := second.SetName("other"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer second.Unmount()
+
+	if err := second.SetName("taken"); nil == err {
+		t.Errorf("Expected an error from taking another store's name, but did not actually get one.")
+	}
+
+	if expected, actual := "taken", first.Name(); expected != actual {
+		t.Errorf("The name of the first store was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+	if expected, actual := "other", second.Name(); expected != actual {
+		t.Errorf("The name of the second store was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+}
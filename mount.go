@@ -0,0 +1,55 @@
+package memdigest
+
+import (
+	"fmt"
+)
+
+const (
+	// mountReadOnly is the argument that, when given to a Mounter after the store, makes the store read-only (see Freeze).
+	//
+	// Example
+	//
+	//	err := mountpoint.Mount("memdigest.SHA1", &mem, "read-only")
+	mountReadOnly string = "read-only"
+
+	// mountMultihash is the argument that, when given to a Mounter after the store, makes the store's Create return multihashes (see SetCreateMultihash).
+	//
+	// Example
+	//
+	//	err := mountpoint.Mount("memdigest.SHA1", &mem, "multihash")
+	mountMultihash string = "multihash"
+)
+
+// mountOptions are the options that can be given to a Mounter after the store.
+type mountOptions struct {
+	readOnly bool
+	multihash bool
+}
+
+// parseMountOptions checks the arguments given to a Mounter after the store, and returns the options they set.
+func parseMountOptions(args []interface{}) (mountOptions, error) {
+	var options mountOptions
+
+	for _, arg := range args {
+		switch arg {
+		case mountReadOnly:
+			options.readOnly = true
+		case mountMultihash:
+			options.multihash = true
+		default:
+			return mountOptions{}, fmt.Errorf("memdigest: Bad Mount Option: expected %q or %q, but actually got %#v", mountReadOnly, mountMultihash, arg)
+		}
+	}
+
+	return options, nil
+}
+
+// apply applies the options to ‘store’.
+func (receiver mountOptions) apply(store *Store) {
+	if receiver.multihash {
+		store.SetCreateMultihash(true)
+	}
+	if receiver.readOnly {
+		store.Freeze()
+	}
+}
@@ -0,0 +1,306 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"sync"
+)
+
+func init() {
+	const name string = "memdigest.Multi"
+
+	var mounter digestfs_driver.Mounter = digestfs_driver.MounterFunc(func(args ...interface{}) (digestfs_driver.MountPoint, error){
+		if expected, actual := 1, len(args); expected != actual {
+			return nil, fmt.Errorf("memdigest: Wrong Number Of Arguments: expected %d, but actually got %d", expected, actual)
+		}
+
+		arg0 := args[0]
+
+		mem, casted := arg0.(*Multi)
+		if !casted {
+			return nil, fmt.Errorf("memdigest: Wrong Type: expected *memdigest.Multi, but actually got %T", arg0)
+		}
+		if nil == mem {
+			return nil, errNilReceiver
+		}
+
+		return mem, nil
+	})
+
+	digestfs_driver.Registry.Register(mounter, name)
+}
+
+// Multi is an in memory content-addressable storage (CAS) that indexes each piece of content under
+// the digests of several hash algorithms at once.
+//
+// For example, a Multi configured with "SHA-1" and "SHA-256" stores content once, but it can be opened
+// by either its SHA-1 digest or its SHA-256 digest.
+//
+// A Multi is created with NewMulti.
+// The zero value of Multi is not usable.
+//
+// *memdigest.Multi fits the digestfs_driver.MountPoint interface.
+type Multi struct {
+	algorithms []algorithm
+
+	mutex sync.RWMutex
+	data []map[string]string // parallel to ‘algorithms’
+}
+
+// NewMulti returns a new *memdigest.Multi that indexes content under each of the algorithms named in ‘algorithms’.
+//
+// The first algorithm is the primary algorithm. It is the one whose digest Create returns.
+//
+// Supported algorithm names are: "SHA-1", "SHA-224", "SHA-256", "SHA-384", "SHA-512", and "SHA-512/256".
+//
+// Example
+//
+//	mem, err := memdigest.NewMulti("SHA-256", "SHA-1")
+func NewMulti(algorithms ...string) (*Multi, error) {
+	if len(algorithms) < 1 {
+		return nil, errNoAlgorithms
+	}
+
+	var multi Multi
+
+	for _, name := range algorithms {
+		algo, found := knownAlgorithms[name]
+		if !found {
+			return nil, digestfs_driver.ErrUnsupportedAlgorithm(name)
+		}
+
+		if 0 <= multi.index(name) {
+			return nil, fmt.Errorf("memdigest: Duplicate Algorithm: %q", name)
+		}
+
+		multi.algorithms = append(multi.algorithms, algo)
+	}
+
+	return &multi, nil
+}
+
+// Algorithms returns the names of the hash algorithms the store indexes content under.
+func (receiver *Multi) Algorithms() []string {
+	if nil == receiver {
+		return nil
+	}
+
+	var names []string
+	for _, algo := range receiver.algorithms {
+		names = append(names, algo.name)
+	}
+
+	return names
+}
+
+func (receiver *Multi) index(algorithm string) int {
+	for i, algo := range receiver.algorithms {
+		if algorithm == algo.name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Create makes *memdigest.Multi fit the digestfs_driver.MountPoint interface.
+//
+// Create stores ‘p’ and returns the digest of ‘p’ for the primary algorithm.
+//
+// The returned digest is in binary form, not hexadecimal.
+func (receiver *Multi) Create(p []byte) (algorithm string, digest string, err error) {
+	if nil == receiver {
+		return "", "", errNilReceiver
+	}
+	if len(receiver.algorithms) < 1 {
+		return "", "", errNoAlgorithms
+	}
+
+	digests, err := receiver.Store(p)
+	if nil != err {
+		return receiver.algorithms[0].name, "", err
+	}
+
+	return receiver.algorithms[0].name, digests[0], nil
+}
+
+// Load returns the content whose ‘algorithm’ digest is ‘digest’, if it is stored.
+func (receiver *Multi) Load(algorithm string, digest []byte) (string, bool) {
+	if nil == receiver {
+		return "", false
+	}
+
+	index := receiver.index(algorithm)
+	if index < 0 {
+		return "", false
+	}
+
+	if receiver.algorithms[index].size != len(digest) {
+		return "", false
+	}
+
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	if nil == receiver.data {
+		return "", false
+	}
+
+	value, found := receiver.data[index][string(digest)]
+
+	return value, found
+}
+
+// Open makes *memdigest.Multi fit the digestfs_driver.MountPoint interface.
+//
+// Open succeeds for any of the algorithms the store was configured with.
+//
+// ‘digest’ can be either a digest, or the multihash of a digest (see Store.Multihash).
+func (receiver *Multi) Open(algorithm string, digest string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	index := receiver.index(algorithm)
+	if index < 0 {
+		return nil, digestfs_driver.ErrUnsupportedAlgorithm(algorithm)
+	}
+
+	if size := receiver.algorithms[index].size; size != len(digest) {
+		if multihashAlgorithm, fromMultihash, ok := decodeMultihash(digest); ok && algorithm == multihashAlgorithm && size == len(fromMultihash) {
+			digest = fromMultihash
+		}
+	}
+
+	value, found := receiver.Load(algorithm, []byte(digest))
+	if !found {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	return newContent(value), nil
+}
+
+// OpenMultihash opens the content whose multihash (see Store.Multihash) is ‘multihash’, using the algorithm the multihash is for.
+//
+// ‘multihash’ is in binary form, not multibase.
+func (receiver *Multi) OpenMultihash(multihash []byte) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	algorithm, digest, ok := decodeMultihash(string(multihash))
+	if !ok {
+		return nil, fmt.Errorf("memdigest: Bad Multihash: %x", multihash)
+	}
+
+	return receiver.Open(algorithm, digest)
+}
+
+// OpenLocation makes *memdigest.Multi fit the digestfs_driver.MountPoint interface.
+//
+// Locations are of the form:
+//
+//	"memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/0"
+//
+// Where "sha-1" is the (lowercase) name of any of the store's algorithms.
+//
+// Multibase-encoded multihashes (ex: "memdigest:multihash(f1220c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0")
+// are also locations, and are opened with the algorithm the multihash is for.
+//
+// RFC 6920 named information URIs (ex: "ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro") are also locations,
+// but not ones that use a truncated suite.
+func (receiver *Multi) OpenLocation(location string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	parsed, ok := parseLocation(location)
+	if !ok {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+	digest, err := hex.DecodeString(parsed.digestHexadecimal)
+	if nil != err {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	for _, algo := range receiver.algorithms {
+		if strings.ToLower(algo.name) == parsed.algorithm {
+			value, found := receiver.Load(algo.name, digest)
+			if !found {
+				return nil, digestfs_driver.ErrContentNotFound(algo.name, string(digest))
+			}
+
+			return locationContent(location, value, parsed)
+		}
+	}
+
+	return nil, digestfs_driver.ErrBadLocation(location)
+}
+
+// Store stores ‘content’ and returns its digests, one for each of the store's algorithms, in the same order as Algorithms.
+//
+// Every digest is calculated in a single pass over ‘content’, and only one copy of ‘content’ is stored.
+//
+// The returned digests are in binary form, not hexadecimal.
+func (receiver *Multi) Store(content []byte) ([]string, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+	if len(receiver.algorithms) < 1 {
+		return nil, errNoAlgorithms
+	}
+
+	var hashes []hash.Hash
+	var writers []io.Writer
+	for _, algo := range receiver.algorithms {
+		h := algo.newHash()
+		hashes = append(hashes, h)
+		writers = append(writers, h)
+	}
+
+	io.MultiWriter(writers...).Write(content)
+
+	var digests []string
+	for _, h := range hashes {
+		digests = append(digests, string(h.Sum(nil)))
+	}
+
+	value := string(content)
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if nil == receiver.data {
+		receiver.data = make([]map[string]string, len(receiver.algorithms))
+		for i := range receiver.data {
+			receiver.data[i] = map[string]string{}
+		}
+	}
+
+	for i, digest := range digests {
+		receiver.data[i][digest] = value
+	}
+
+	return digests, nil
+}
+
+// Unmount makes *memdigest.Multi fit the digestfs_driver.MountPoint interface.
+//
+// Unmount will never return an error, but will (conceptually) remove all content it was previously storing.
+func (receiver *Multi) Unmount() error {
+	if nil == receiver {
+		return nil
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	receiver.data = nil
+
+	return nil
+}
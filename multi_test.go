@@ -0,0 +1,142 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"github.com/reiver/go-digestfs"
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"testing"
+)
+
+func TestMultiAsDigestFSDriverMountPoint(t *testing.T) {
+
+	var mountpoint digestfs_driver.MountPoint = new(memdigest.Multi) // THIS IS WHAT ACTUALLY MATTERS!
+
+	if nil == mountpoint {
+		t.Error("This should never happen.")
+	}
+}
+
+func TestMulti(t *testing.T) {
+
+	tests := []struct{
+		Content string
+		ExpectedSHA1 string
+		ExpectedSHA256 string
+	}{
+		{
+			Content: "Hello world!",
+			ExpectedSHA1: "d3486ae9136e7856bc42212385ea797094475802",
+			ExpectedSHA256: "c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a",
+		},
+		{
+			Content: "apple",
+			ExpectedSHA1: "d0be2dc421be4fcd0172e5afceea3970e2f3d940",
+			ExpectedSHA256: "3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b",
+		},
+	}
+
+	mem, err := memdigest.NewMulti("SHA-256", "SHA-1")
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	for testNumber, test := range tests {
+
+		algorithm, digest, err := mem.Create([]byte(test.Content))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := "SHA-256", algorithm; expected != actual {
+			t.Errorf("For test #%d, the algorithm that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		if expected, actual := test.ExpectedSHA256, fmt.Sprintf("%x", digest); expected != actual {
+			t.Errorf("For test #%d, the digest that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+
+	for testNumber, test := range tests {
+
+		for _, datum := range []struct{
+			Algorithm string
+			Digest string
+			Location string
+		}{
+			{
+				Algorithm: "SHA-1",
+				Digest: test.ExpectedSHA1,
+				Location: "memdigest:sha-1:hexadecimal("+test.ExpectedSHA1+")/0",
+			},
+			{
+				Algorithm: "SHA-256",
+				Digest: test.ExpectedSHA256,
+				Location: "memdigest:sha-256:hexadecimal("+test.ExpectedSHA256+")/0",
+			},
+		} {
+			digest, err := hex.DecodeString(datum.Digest)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				continue
+			}
+
+			for _, open := range []func() (digestfs_driver.Content, error){
+				func() (digestfs_driver.Content, error) { return mem.Open(datum.Algorithm, string(digest)) },
+				func() (digestfs_driver.Content, error) { return mem.OpenLocation(datum.Location) },
+			} {
+				content, err := open()
+				if nil != err {
+					t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+					t.Logf("Algorithm: %q", datum.Algorithm)
+					continue
+				}
+
+				r := io.NewSectionReader(content, 0, int64(content.Len()))
+				value, err := ioutil.ReadAll(r)
+				if nil != err {
+					t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+					continue
+				}
+
+				if expected, actual := test.Content, string(value); expected != actual {
+					t.Errorf("For test #%d, the actual value is not what was expected.", testNumber)
+					t.Logf("Algorithm: %q", datum.Algorithm)
+					t.Logf("EXPECTED: %q", expected)
+					t.Logf("ACTUAL:   %q", actual)
+					continue
+				}
+			}
+		}
+	}
+
+	{
+		_, err := mem.Open("SHA-512", "")
+		switch err.(type) {
+		case digestfs.UnsupportedAlgorithm:
+			// Nothing here.
+		default:
+			t.Errorf("Expected error to be UnsupportedAlgorithm, but actually wasn't: (%T) %q", err, err)
+		}
+	}
+
+	{
+		_, err := memdigest.NewMulti("SHA-1", "MD5")
+		if nil == err {
+			t.Errorf("Expected an error for an unsupported algorithm, but did not actually get one.")
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// multihashCodes are the multihash codes (see https://github.com/multiformats/multicodec) of the algorithms memdigest knows about.
+var multihashCodes = map[string]uint64{
+	algorithmSHA1:       0x11,
+	algorithmSHA256:     0x12,
+	algorithmSHA512:     0x13,
+	algorithmSHA384:     0x20,
+	algorithmSHA224:     0x1013,
+	algorithmSHA512_256: 0x1015,
+}
+
+// encodeMultihash returns the multihash of ‘digest’, which was calculated with the algorithm with the multihash code ‘code’.
+//
+// A multihash is:
+//
+//	code       unsigned varint: the multihash code of the algorithm
+//	length     unsigned varint: the length of the digest
+//	digest     ‘length’ bytes: the digest
+func encodeMultihash(code uint64, digest string) string {
+	var p []byte
+
+	p = binary.AppendUvarint(p, code)
+	p = binary.AppendUvarint(p, uint64(len(digest)))
+	p = append(p, digest...)
+
+	return string(p)
+}
+
+// decodeMultihash returns the name of the algorithm, and the digest, in the multihash ‘multihash’.
+//
+// The digest might be truncated (i.e., shorter than the algorithm's digests).
+func decodeMultihash(multihash string) (algorithm string, digest string, ok bool) {
+	p := []byte(multihash)
+
+	code, n := binary.Uvarint(p)
+	if n <= 0 {
+		return "", "", false
+	}
+	p = p[n:]
+
+	length, n := binary.Uvarint(p)
+	if n <= 0 {
+		return "", "", false
+	}
+	p = p[n:]
+
+	if uint64(len(p)) != length {
+		return "", "", false
+	}
+
+	for name, c := range multihashCodes {
+		if code != c {
+			continue
+		}
+
+		if knownAlgorithms[name].size < len(p) {
+			return "", "", false
+		}
+
+		return name, string(p), true
+	}
+
+	return "", "", false
+}
+
+// base58btc is the alphabet of the base58btc multibase encoding.
+const base58btc string = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func decodeBase58(s string) ([]byte, bool) {
+	var n big.Int
+	var radix = big.NewInt(58)
+
+	for _, r := range s {
+		index := strings.IndexRune(base58btc, r)
+		if index < 0 {
+			return nil, false
+		}
+
+		n.Mul(&n, radix)
+		n.Add(&n, big.NewInt(int64(index)))
+	}
+
+	// Each leading '1' is a leading zero byte.
+	var zeros int
+	for zeros < len(s) && '1' == s[zeros] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), true
+}
+
+// decodeMultibase decodes the multibase (see https://github.com/multiformats/multibase) string ‘s’.
+//
+// The supported encodings are:
+//
+//	'f'  base16 (lowercase)
+//	'F'  base16 (uppercase)
+//	'b'  base32 (lowercase, without padding)
+//	'B'  base32 (uppercase, without padding)
+//	'z'  base58btc
+//	'm'  base64 (without padding)
+//	'M'  base64 (with padding)
+//	'u'  base64url (without padding)
+//	'U'  base64url (with padding)
+func decodeMultibase(s string) ([]byte, bool) {
+	if "" == s {
+		return nil, false
+	}
+
+	var p []byte
+	var err error
+
+	switch encoded := s[1:]; s[0] {
+	case 'f', 'F':
+		p, err = hex.DecodeString(encoded)
+	case 'b', 'B':
+		p, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(encoded))
+	case 'z':
+		var ok bool
+		p, ok = decodeBase58(encoded)
+		if !ok {
+			return nil, false
+		}
+	case 'm':
+		p, err = base64.RawStdEncoding.DecodeString(encoded)
+	case 'M':
+		p, err = base64.StdEncoding.DecodeString(encoded)
+	case 'u':
+		p, err = base64.RawURLEncoding.DecodeString(encoded)
+	case 'U':
+		p, err = base64.URLEncoding.DecodeString(encoded)
+	default:
+		return nil, false
+	}
+	if nil != err {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// parseMultihashLocation parses the part of a location of the form:
+//
+//	"memdigest:multihash(zQmbHQieckNGj2KwBhpzkGSLDgezGnArL6eeuvb87YLX665)/0"
+//
+// that is between "memdigest:" and "/0"; i.e., the "multihash(...)".
+//
+// And returns the (lowercase) algorithm name, and the hexadecimal digest (which might be abbreviated, if the multihash's digest was truncated).
+func parseMultihashLocation(s string) (algorithm string, digestHexadecimal string, ok bool) {
+	const prefix string = "multihash("
+	const suffix string = ")"
+
+	if !strings.HasPrefix(s, prefix) {
+		return "", "", false
+	}
+	if !strings.HasSuffix(s, suffix) {
+		return "", "", false
+	}
+
+	multihash, ok := decodeMultibase(s[len(prefix):len(s)-len(suffix)])
+	if !ok {
+		return "", "", false
+	}
+
+	algorithm, digest, ok := decodeMultihash(string(multihash))
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.ToLower(algorithm), hex.EncodeToString([]byte(digest)), true
+}
+
+// Multihash returns the multihash (see https://github.com/multiformats/multihash) of ‘digest’, which is self-describing:
+// it says which algorithm the digest was calculated with.
+//
+// ‘digest’ is in binary form, not hexadecimal; and so is the returned multihash.
+//
+// Only the algorithms memdigest knows about ("SHA-1", "SHA-224", "SHA-256", "SHA-384", "SHA-512", and "SHA-512/256") have multihashes.
+//
+// Open and OpenMultihash accept multihashes.
+//
+// Example
+//
+//	multihash, err := mem.Multihash(digest)
+func (receiver *Store) Multihash(digest []byte) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	code, found := multihashCodes[receiver.algorithm]
+	if !found {
+		return "", fmt.Errorf("memdigest: No Multihash: %s does not have a multihash code", receiver.algorithm)
+	}
+
+	if receiver.size != len(digest) {
+		return "", fmt.Errorf("memdigest: Wrong Digest Size: expected %d, but actually got %d", receiver.size, len(digest))
+	}
+
+	return encodeMultihash(code, string(digest)), nil
+}
+
+// fromMultihash returns the digest in ‘multihash’, if it is a multihash of a whole digest for the store's algorithm.
+func (receiver *Store) fromMultihash(multihash string) (string, bool) {
+	algorithm, digest, ok := decodeMultihash(multihash)
+	if !ok {
+		return "", false
+	}
+	if receiver.algorithm != algorithm || receiver.size != len(digest) {
+		return "", false
+	}
+
+	return digest, true
+}
+
+// OpenMultihash opens the content whose multihash (see Multihash) is ‘multihash’.
+//
+// Unlike Open, OpenMultihash does not need to be told the algorithm, since a multihash says which algorithm it is for.
+//
+// ‘multihash’ is in binary form, not multibase.
+func (receiver *Store) OpenMultihash(multihash []byte) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, errNilReceiver
+	}
+
+	algorithm, digest, ok := decodeMultihash(string(multihash))
+	if !ok {
+		return nil, fmt.Errorf("memdigest: Bad Multihash: %x", multihash)
+	}
+
+	return receiver.Open(algorithm, digest)
+}
+
+// SetCreateMultihash sets whether Create returns multihashes (see Multihash), rather than digests.
+//
+// Since Open accepts multihashes too, a multihash returned by Create can be given to Open.
+//
+// This can also be set when the store is mounted, by giving the Mounter the "multihash" argument.
+func (receiver *Store) SetCreateMultihash(enabled bool) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.createMultihash.Store(enabled)
+}
@@ -0,0 +1,206 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+	"github.com/reiver/go-digestfs"
+
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"testing"
+)
+
+func TestSHA256OpenLocationMultihash(t *testing.T) {
+
+	var mem memdigest.SHA256
+	mem.Store([]byte("Hello world!"))
+
+	tests := []string{
+		"memdigest:multihash(f1220c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0",
+		"memdigest:multihash(F1220C0535E4BE2B79FFD93291305436BF889314E4A3FAEC05ECFFCBB7DF31AD9E51A)/0",
+		"memdigest:multihash(zQmbHQieckNGj2KwBhpzkGSLDgezGnArL6eeuvb87YLX665)/0",
+		"memdigest:multihash(bciqmau26jprlph75smurgbkdnp4ismkoji725qc6z76lw7ptdlm6kgq)/0",
+		"memdigest:multihash(BCIQMAU26JPRLPH75SMURGBKDNP4ISMKOJI725QC6Z76LW7PTDLM6KGQ)/0",
+		"memdigest:multihash(uEiDAU15L4ref_ZMpEwVDa_iJMU5KP67AXs_8u33zGtnlGg)/0",
+		"memdigest:multihash(MEiDAU15L4ref/ZMpEwVDa/iJMU5KP67AXs/8u33zGtnlGg==)/0",
+		"memdigest:multihash(f1204c0535e4b)/0", // truncated, and so resolved as an abbreviation
+	}
+
+	for testNumber, location := range tests {
+
+		content, err := mem.OpenLocation(location)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			t.Logf("LOCATION: %q", location)
+			continue
+		}
+
+		p, _ := io.ReadAll(io.NewSectionReader(content, 0, 1<<20))
+		if expected, actual := "Hello world!", string(p); expected != actual {
+			t.Errorf("For test #%d, the content that was actually opened was not what was expected.", testNumber)
+			t.Logf("LOCATION: %q", location)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func TestSHA256OpenLocationMultihashBad(t *testing.T) {
+
+	var mem memdigest.SHA256
+	mem.Store([]byte("Hello world!"))
+
+	tests := []string{
+		// SHA-1, not SHA-256.
+		"memdigest:multihash(f1114d3486ae9136e7856bc42212385ea797094475802)/0",
+		// Length does not match.
+		"memdigest:multihash(f1221c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0",
+		// Unknown multihash code.
+		"memdigest:multihash(f9920c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0",
+		// Unknown multibase encoding.
+		"memdigest:multihash(x1220c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0",
+		// Not base58btc.
+		"memdigest:multihash(z0OIl)/0",
+		"memdigest:multihash()/0",
+	}
+
+	for testNumber, location := range tests {
+		if _, err := mem.OpenLocation(location); nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			t.Logf("LOCATION: %q", location)
+		}
+	}
+}
+
+func TestMultiOpenMultihash(t *testing.T) {
+
+	mem, err := memdigest.NewMulti("SHA-256", "SHA-1")
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	mem.Store([]byte("Hello world!"))
+
+	digest1 := sha1.Sum([]byte("Hello world!"))
+	digest256 := sha256.Sum256([]byte("Hello world!"))
+
+	tests := [][]byte{
+		append([]byte{0x11, sha1.Size}, digest1[:]...),
+		append([]byte{0x12, sha256.Size}, digest256[:]...),
+	}
+
+	for testNumber, multihash := range tests {
+
+		content, err := mem.OpenMultihash(multihash)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		p, _ := io.ReadAll(io.NewSectionReader(content, 0, 1<<20))
+		if expected, actual := "Hello world!", string(p); expected != actual {
+			t.Errorf("For test #%d, the content that was actually opened was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+
+	location := fmt.Sprintf("memdigest:multihash(f1114%x)/0", digest1)
+	if _, err := mem.OpenLocation(location); nil != err {
+		t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+}
+
+func TestMultiOpenWithMultihash(t *testing.T) {
+
+	mem, err := memdigest.NewMulti("SHA-256", "SHA-1")
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	mem.Store([]byte("Hello world!"))
+
+	var sha1Store memdigest.SHA1
+	digest1, _ := sha1Store.Store([]byte("Hello world!"))
+	multihash1, err := sha1Store.Multihash(digest1[:])
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	var sha256Store memdigest.SHA256
+	digest256, _ := sha256Store.Store([]byte("Hello world!"))
+	multihash256, err := sha256Store.Multihash(digest256[:])
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	tests := []struct{
+		Algorithm string
+		Digest string
+	}{
+		{Algorithm: "SHA-1", Digest: multihash1},
+		{Algorithm: "SHA-256", Digest: multihash256},
+	}
+
+	for testNumber, test := range tests {
+
+		content, err := mem.Open(test.Algorithm, test.Digest)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		p, _ := io.ReadAll(io.NewSectionReader(content, 0, 1<<20))
+		if expected, actual := "Hello world!", string(p); expected != actual {
+			t.Errorf("For test #%d, the content that was actually opened was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+
+	// A multihash for a different algorithm than the one asked for is not opened.
+	if _, err := mem.Open("SHA-256", multihash1); nil == err {
+		t.Errorf("Expected an error opening a SHA-1 multihash as SHA-256, but did not actually get one.")
+	}
+}
+
+func TestSHA1CreateMultihash(t *testing.T) {
+
+	var mem memdigest.SHA1
+	defer mem.Unmount()
+
+	var mountpoint digestfs.MountPoint
+	if err := mountpoint.Mount("memdigest.SHA1", &mem, "multihash"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	algorithm, digest, err := mem.Create([]byte("Hello world!"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if expected, actual := "1114d3486ae9136e7856bc42212385ea797094475802", fmt.Sprintf("%x", digest); expected != actual {
+		t.Errorf("The multihash that Create actually returned was not what was expected.")
+		t.Logf("EXPECTED: %s", expected)
+		t.Logf("ACTUAL:   %s", actual)
+	}
+
+	// What Create returns, Open accepts.
+	if _, err := mem.Open(algorithm, digest); nil != err {
+		t.Errorf("Did not expect an error opening the multihash, but actually got one: (%T) %q", err, err)
+	}
+
+	mem.SetCreateMultihash(false)
+
+	_, digest, _ = mem.Create([]byte("Hello world!"))
+	if expected, actual := "d3486ae9136e7856bc42212385ea797094475802", fmt.Sprintf("%x", digest); expected != actual {
+		t.Errorf("The digest that Create actually returned was not what was expected.")
+		t.Logf("EXPECTED: %s", expected)
+		t.Logf("ACTUAL:   %s", actual)
+	}
+}
@@ -0,0 +1,120 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// namedInformationScheme is the scheme of RFC 6920 named information URIs.
+const namedInformationScheme string = "ni:"
+
+// namedInformationSuite is an entry in the IANA "Named Information Hash Algorithm Registry" (see RFC 6920, section 9.4).
+type namedInformationSuite struct {
+	name string // the suite name, as used in ni URIs (ex: "sha-256-128")
+	algorithm string // the name of the algorithm the suite uses (ex: "SHA-256")
+	size int // how many bytes of the digest the suite keeps
+}
+
+// namedInformationSuites are the suites that use an algorithm memdigest knows about.
+//
+// (SHA-1, SHA-224, and SHA-512/256 do not have suites, and so content stored with them cannot be named with ni URIs.)
+var namedInformationSuites = []namedInformationSuite{
+	{"sha-256", "SHA-256", 32},
+	{"sha-256-128", "SHA-256", 16},
+	{"sha-256-120", "SHA-256", 15},
+	{"sha-256-96", "SHA-256", 12},
+	{"sha-256-64", "SHA-256", 8},
+	{"sha-256-32", "SHA-256", 4},
+	{"sha-384", "SHA-384", 48},
+	{"sha-512", "SHA-512", 64},
+}
+
+func namedInformationSuiteByName(name string) (namedInformationSuite, bool) {
+	name = strings.ToLower(name)
+
+	for _, suite := range namedInformationSuites {
+		if suite.name == name {
+			return suite, true
+		}
+	}
+
+	return namedInformationSuite{}, false
+}
+
+// parseNamedInformation parses an RFC 6920 named information URI of the form:
+//
+//	"ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro"
+//
+// (The authority, ex: "ni://example.com/sha-256;...", and the query, ex: "ni:///sha-256;...?ct=text/plain", are allowed, but ignored.)
+//
+// And returns the (lowercase) algorithm name, and the hexadecimal digest.
+// If the suite is one of the truncated ones (ex: "sha-256-32"), then the hexadecimal digest is abbreviated (see Resolve).
+func parseNamedInformation(location string) (algorithm string, digestHexadecimal string, ok bool) {
+	if !strings.HasPrefix(location, namedInformationScheme+"//") {
+		return "", "", false
+	}
+	s := location[len(namedInformationScheme+"//"):]
+
+	if index := strings.IndexByte(s, '?'); 0 <= index {
+		s = s[:index]
+	}
+
+	// Skip the authority.
+	index := strings.IndexByte(s, '/')
+	if index < 0 {
+		return "", "", false
+	}
+	s = s[index+1:]
+
+	name, value, found := strings.Cut(s, ";")
+	if !found {
+		return "", "", false
+	}
+
+	suite, found := namedInformationSuiteByName(name)
+	if !found {
+		return "", "", false
+	}
+
+	p, err := base64.RawURLEncoding.DecodeString(value)
+	if nil != err {
+		return "", "", false
+	}
+	if suite.size != len(p) {
+		return "", "", false
+	}
+
+	return strings.ToLower(suite.algorithm), hex.EncodeToString(p), true
+}
+
+// NamedInformation returns the RFC 6920 named information URI for the stored content whose digest is ‘digest’, using the suite named ‘suite’.
+//
+// ‘suite’ is the name of a suite for the store's algorithm; either the whole digest (ex: "sha-256"), or a truncated one (ex: "sha-256-128").
+// Only SHA-256, SHA-384, and SHA-512 have suites.
+//
+// OpenLocation accepts what NamedInformation returns. (Truncated digests are resolved the same way as abbreviated ones, see Resolve.)
+//
+// Example
+//
+//	// location == "ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro"
+//	location, err := mem.NamedInformation(digest, "sha-256")
+func (receiver *Store) NamedInformation(digest []byte, suite string) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	found, ok := namedInformationSuiteByName(suite)
+	if !ok || receiver.algorithm != found.algorithm {
+		return "", fmt.Errorf("memdigest: Unsupported Named Information Suite: %q is not a suite for %s", suite, receiver.algorithm)
+	}
+
+	if _, stored := receiver.loadBytes(digest); !stored {
+		return "", digestfs_driver.ErrContentNotFound(receiver.algorithm, string(digest))
+	}
+
+	return namedInformationScheme + "///" + found.name + ";" + base64.RawURLEncoding.EncodeToString(digest[:found.size]), nil
+}
@@ -0,0 +1,156 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+	"crypto/sha256"
+	"io"
+
+	"testing"
+)
+
+func TestSHA256OpenLocationNamedInformation(t *testing.T) {
+
+	var mem memdigest.SHA256
+	mem.Store([]byte("Hello world!"))
+	mem.Store([]byte("apple"))
+
+	tests := []struct{
+		Location string
+		Expected string
+	}{
+		{
+			Location: "ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+			Expected: "Hello world!",
+		},
+		{
+			Location: "ni://example.com/sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+			Expected: "Hello world!",
+		},
+		{
+			Location: "ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro?ct=text/plain",
+			Expected: "Hello world!",
+		},
+		{
+			Location: "ni:///SHA-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+			Expected: "Hello world!",
+		},
+		{
+			Location: "ni:///sha-256-128;wFNeS-K3n_2TKRMFQ2v4iQ",
+			Expected: "Hello world!",
+		},
+		{
+			Location: "ni:///sha-256-32;wFNeSw",
+			Expected: "Hello world!",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		content, err := mem.OpenLocation(test.Location)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			t.Logf("LOCATION: %q", test.Location)
+			continue
+		}
+
+		p, _ := io.ReadAll(io.NewSectionReader(content, 0, 1<<20))
+		if expected, actual := test.Expected, string(p); expected != actual {
+			t.Errorf("For test #%d, the content that was actually opened was not what was expected.", testNumber)
+			t.Logf("LOCATION: %q", test.Location)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func TestSHA256OpenLocationNamedInformationBad(t *testing.T) {
+
+	var mem memdigest.SHA256
+	mem.Store([]byte("Hello world!"))
+
+	tests := []string{
+		"ni:sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+		"ni:///sha-256wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+		"ni:///sha-1;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+		"ni:///sha-384;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+		"ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5R",
+		"ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro=",
+		"ni:///sha-256-128;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+	}
+
+	for testNumber, location := range tests {
+		if _, err := mem.OpenLocation(location); nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			t.Logf("LOCATION: %q", location)
+		}
+	}
+}
+
+func TestSHA256NamedInformation(t *testing.T) {
+
+	var mem memdigest.SHA256
+	digest, _ := mem.Store([]byte("Hello world!"))
+
+	tests := []struct{
+		Suite string
+		Expected string
+	}{
+		{
+			Suite: "sha-256",
+			Expected: "ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro",
+		},
+		{
+			Suite: "sha-256-128",
+			Expected: "ni:///sha-256-128;wFNeS-K3n_2TKRMFQ2v4iQ",
+		},
+		{
+			Suite: "sha-256-32",
+			Expected: "ni:///sha-256-32;wFNeSw",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		actual, err := mem.NamedInformation(digest[:], test.Suite)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, the named information URI that was actually returned was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		// What NamedInformation returns, OpenLocation accepts.
+		if _, err := mem.OpenLocation(actual); nil != err {
+			t.Errorf("For test #%d, did not expect an error opening the named information URI, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+	}
+
+	if _, err := mem.NamedInformation(digest[:], "sha-384"); nil == err {
+		t.Errorf("Expected an error from a suite for a different algorithm, but did not actually get one.")
+	}
+
+	notStored := sha256.Sum256([]byte("not stored"))
+	if _, err := mem.NamedInformation(notStored[:], "sha-256"); nil == err {
+		t.Errorf("Expected an error from content that is not stored, but did not actually get one.")
+	}
+}
+
+func TestStoreNamedInformationSHA1(t *testing.T) {
+
+	store := memdigest.NewStore("SHA-1", sha1.Size, sha1.New)
+	digest, _ := store.Store([]byte("Hello world!"))
+
+	// SHA-1 does not have a named information suite.
+	if _, err := store.NamedInformation([]byte(digest), "sha-1"); nil == err {
+		t.Errorf("Expected an error, but did not actually get one.")
+	}
+}
@@ -0,0 +1,95 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"fmt"
+)
+
+// Pinned is the error returned when content cannot be removed because it is pinned.
+//
+// Content is pinned with Pin, and unpinned with Unpin.
+type Pinned struct {
+	Digest string // in binary form, not hexadecimal
+	Count int
+}
+
+func (receiver Pinned) Error() string {
+	return fmt.Sprintf("memdigest: Pinned: content with digest %x is pinned %d time(s)", receiver.Digest, receiver.Count)
+}
+
+// Pin pins the content whose digest is ‘digest’, so that it is not removed by Delete, by eviction, or by expiry,
+// until it is unpinned with Unpin.
+//
+// Pins are counted. Content that was pinned N times has to be unpinned N times before it can be removed again.
+// This lets several owners of the same content each pin it, without one owner removing it out from under the others.
+func (receiver *Store) Pin(digest []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if receiver.frozen.Load() {
+		return ErrReadOnly
+	}
+
+	key := string(digest)
+
+	if receiver.size != len(digest) {
+		return digestfs_driver.ErrContentNotFound(receiver.algorithm, key)
+	}
+
+	found, err := receiver.shard(key).pin(key, &receiver.lru)
+	if nil != err {
+		return err
+	}
+	if !found {
+		return digestfs_driver.ErrContentNotFound(receiver.algorithm, key)
+	}
+
+	return nil
+}
+
+// Unpin undoes one Pin of the content whose digest is ‘digest’.
+func (receiver *Store) Unpin(digest []byte) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if receiver.frozen.Load() {
+		return ErrReadOnly
+	}
+
+	key := string(digest)
+
+	if receiver.size != len(digest) {
+		return digestfs_driver.ErrContentNotFound(receiver.algorithm, key)
+	}
+
+	found, pinned, err := receiver.shard(key).unpin(key, &receiver.lru)
+	if nil != err {
+		return err
+	}
+	if !found {
+		return digestfs_driver.ErrContentNotFound(receiver.algorithm, key)
+	}
+	if !pinned {
+		return errNotPinned
+	}
+
+	receiver.evict()
+
+	return nil
+}
+
+// Pins returns how many times the content whose digest is ‘digest’ is pinned.
+func (receiver *Store) Pins(digest []byte) int {
+	if nil == receiver {
+		return 0
+	}
+
+	if receiver.size != len(digest) {
+		return 0
+	}
+
+	key := string(digest)
+
+	return receiver.shard(key).pins(key)
+}
@@ -0,0 +1,129 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+	"time"
+
+	"testing"
+)
+
+func TestSHA1PinDelete(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	digest, err := mem.Store([]byte("apple"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	// Two owners pin the same content.
+	for i := 0; i < 2; i++ {
+		if err := mem.Pin(digest[:]); nil != err {
+			t.Fatalf("For pin #%d, did not expect an error, but actually got one: (%T) %q", i, err, err)
+		}
+	}
+
+	if expected, actual := 2, mem.Pins(digest[:]); expected != actual {
+		t.Errorf("The number of pins was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	// One owner is done with it.
+	if err := mem.Unpin(digest[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	{
+		removed, err := mem.Delete(digest[:])
+		if removed {
+			t.Errorf("Did not expect pinned content to be removed, but it actually was.")
+		}
+
+		pinned, casted := err.(memdigest.Pinned)
+		if !casted {
+			t.Fatalf("Expected error to be memdigest.Pinned, but actually wasn't: (%T) %q", err, err)
+		}
+		if expected, actual := 1, pinned.Count; expected != actual {
+			t.Errorf("The number of pins reported was not what was expected.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+	}
+
+	// The other owner is done with it.
+	if err := mem.Unpin(digest[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	if err := mem.Unpin(digest[:]); nil == err {
+		t.Errorf("Expected an error unpinning content that is not pinned, but did not actually get one.")
+	}
+
+	if removed, err := mem.Delete(digest[:]); nil != err || !removed {
+		t.Errorf("Expected unpinned content to be removed, but it actually wasn't: removed=%t err=%v", removed, err)
+	}
+
+	if err := mem.Pin(digest[:]); nil == err {
+		t.Errorf("Expected an error pinning content that is not stored, but did not actually get one.")
+	}
+}
+
+func TestSHA1PinEviction(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	mem.SetLimits(0, 1)
+
+	apple, _ := mem.Store([]byte("apple"))
+	if err := mem.Pin(apple[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	banana, _ := mem.Store([]byte("BANANA"))
+
+	if _, found := mem.Load(apple[:]); !found {
+		t.Errorf("Did not expect pinned content to be evicted, but it actually was.")
+	}
+	if _, found := mem.Load(banana[:]); found {
+		t.Errorf("Expected unpinned content to be evicted, but it actually wasn't.")
+	}
+}
+
+func TestSHA1PinExpiry(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	// (The sleeps here are only ever for longer than the TTL, so that a delay cannot make this fail.)
+	const ttl = 10 * time.Millisecond
+
+	digest, err := mem.StoreWithTTL([]byte("apple"), ttl)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	if err := mem.Pin(digest[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	time.Sleep(5 * ttl)
+	mem.Sweep()
+
+	if _, found := mem.Load(digest[:]); !found {
+		t.Errorf("Did not expect pinned content to expire, but it actually did.")
+	}
+
+	if err := mem.Unpin(digest[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	time.Sleep(5 * ttl)
+
+	if _, found := mem.Load(digest[:]); found {
+		t.Errorf("Expected unpinned content to expire, but it actually didn't.")
+	}
+
+	if expected, actual := sha1.Sum([]byte("apple")), digest; expected != actual {
+		t.Errorf("The SHA-1 that was actually gotten was not what was expected.")
+	}
+}
@@ -0,0 +1,274 @@
+package memdigest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AmbiguousPrefix is the error returned by Resolve when more than one stored digest begins with the prefix.
+type AmbiguousPrefix struct {
+	Prefix string
+	Candidates []string // in binary form, not hexadecimal, and in order
+}
+
+func (receiver AmbiguousPrefix) Error() string {
+	var candidates []string
+	for _, candidate := range receiver.Candidates {
+		candidates = append(candidates, hex.EncodeToString([]byte(candidate)))
+	}
+
+	return fmt.Sprintf("memdigest: Ambiguous Prefix: %q matches %d digests: %s", receiver.Prefix, len(candidates), strings.Join(candidates, ", "))
+}
+
+// PrefixNotFound is the error returned by Resolve when no stored digest begins with the prefix.
+type PrefixNotFound struct {
+	Prefix string
+}
+
+func (receiver PrefixNotFound) Error() string {
+	return fmt.Sprintf("memdigest: Prefix Not Found: no digest begins with %q", receiver.Prefix)
+}
+
+// hexPrefix is a parsed (possibly odd-length) hexadecimal prefix of a digest.
+type hexPrefix struct {
+	bytes string // the whole bytes of the prefix
+	odd bool // whether the prefix ends with a half a byte
+	nibble byte // the half a byte the prefix ends with, if ‘odd’
+}
+
+func parseHexPrefix(s string) (hexPrefix, bool) {
+	var prefix hexPrefix
+
+	if 1 == len(s)%2 {
+		nibble, err := hex.DecodeString("0" + s[len(s)-1:])
+		if nil != err {
+			return prefix, false
+		}
+
+		prefix.odd = true
+		prefix.nibble = nibble[0]
+		s = s[:len(s)-1]
+	}
+
+	p, err := hex.DecodeString(s)
+	if nil != err {
+		return prefix, false
+	}
+	prefix.bytes = string(p)
+
+	return prefix, true
+}
+
+// lowest returns the lowest key that could begin with the prefix.
+func (receiver hexPrefix) lowest() string {
+	if receiver.odd {
+		return receiver.bytes + string([]byte{receiver.nibble << 4})
+	}
+
+	return receiver.bytes
+}
+
+func (receiver hexPrefix) matches(key string) bool {
+	if !strings.HasPrefix(key, receiver.bytes) {
+		return false
+	}
+	if !receiver.odd {
+		return true
+	}
+	if len(key) <= len(receiver.bytes) {
+		return false
+	}
+
+	return receiver.nibble == key[len(receiver.bytes)]>>4
+}
+
+// shards returns the indexes of the shards that keys beginning with the prefix could be in.
+func (receiver hexPrefix) shards() []int {
+	if "" != receiver.bytes {
+		return []int{shardIndex(receiver.bytes[0])}
+	}
+
+	seen := map[int]bool{}
+
+	var indexes []int
+	for low := byte(0); low < 16; low++ {
+		index := shardIndex(receiver.nibble<<4 | low)
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+
+		indexes = append(indexes, index)
+	}
+
+	return indexes
+}
+
+// buildIndex builds the shard's index (of its keys, in order), if it is not already built.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) buildIndex() {
+	if receiver.indexed {
+		return
+	}
+
+	index := make([]string, 0, len(receiver.data))
+	for key := range receiver.data {
+		index = append(index, key)
+	}
+	sort.Strings(index)
+
+	receiver.index = index
+	receiver.indexed = true
+}
+
+// indexAdd adds ‘key’ to the shard's index (keeping it in order), if the index is built and ‘key’ is not already in it.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) indexAdd(key string) {
+	if !receiver.indexed {
+		return
+	}
+
+	i := sort.SearchStrings(receiver.index, key)
+	if i < len(receiver.index) && key == receiver.index[i] {
+		return
+	}
+
+	receiver.index = append(receiver.index, "")
+	copy(receiver.index[i+1:], receiver.index[i:])
+	receiver.index[i] = key
+}
+
+// indexRemove removes ‘key’ from the shard's index, if the index is built.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) indexRemove(key string) {
+	if !receiver.indexed {
+		return
+	}
+
+	i := sort.SearchStrings(receiver.index, key)
+	if len(receiver.index) <= i || key != receiver.index[i] {
+		return
+	}
+
+	last := len(receiver.index) - 1
+
+	copy(receiver.index[i:], receiver.index[i+1:])
+	receiver.index[last] = ""
+	receiver.index = receiver.index[:last]
+}
+
+// indexRetain removes, from the shard's index, the keys that are no longer in ‘data’, if the index is built.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) indexRetain() {
+	if !receiver.indexed {
+		return
+	}
+
+	index := receiver.index[:0]
+	for _, key := range receiver.index {
+		if _, found := receiver.data[key]; found {
+			index = append(index, key)
+		}
+	}
+
+	// (So that the removed keys are not kept alive by the rest of the slice.)
+	clear(receiver.index[len(index):])
+
+	receiver.index = index
+}
+
+// find returns the keys, in order, in the shard that begin with ‘prefix’, and whose content has not expired as of ‘now’ (or is pinned).
+//
+// The index is built the first time it is needed, and kept up to date after that.
+// If ‘locked’ is false (because the store is frozen), then no lock is taken, and the index must already be built.
+func (receiver *shard) find(prefix hexPrefix, now time.Time, locked bool) []string {
+	if locked {
+		for {
+			receiver.mutex.RLock()
+			if receiver.indexed {
+				break
+			}
+			receiver.mutex.RUnlock()
+
+			receiver.mutex.Lock()
+			receiver.buildIndex()
+			receiver.mutex.Unlock()
+		}
+		defer receiver.mutex.RUnlock()
+	}
+
+	var keys []string
+
+	for i := sort.SearchStrings(receiver.index, prefix.lowest()); i < len(receiver.index); i++ {
+		key := receiver.index[i]
+		if !prefix.matches(key) {
+			break
+		}
+
+		e := receiver.data[key]
+		if nil == e {
+			continue
+		}
+		if e.expired(now) && e.pins < 1 {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Resolve returns the digest of the stored content whose hexadecimal digest begins with ‘prefix’
+// (as with abbreviated git object names), if there is exactly one.
+//
+// If more than one digest begins with ‘prefix’, then Resolve returns a memdigest.AmbiguousPrefix error, which lists them.
+// If none do, then Resolve returns a memdigest.PrefixNotFound error.
+//
+// Resolve does not scan all the content; each shard keeps an index of its digests, in order, which is searched instead.
+// (The index is built the first time Resolve is called, and is kept up to date, as content is stored and removed, after that.)
+//
+// The returned digest is in binary form, not hexadecimal.
+//
+// Example
+//
+//	digest, err := mem.Resolve("70cc03f6")
+func (receiver *Store) Resolve(prefix string) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	if "" == prefix || 2*receiver.size < len(prefix) {
+		return "", fmt.Errorf("memdigest: Bad Prefix: %q", prefix)
+	}
+
+	parsed, ok := parseHexPrefix(prefix)
+	if !ok {
+		return "", fmt.Errorf("memdigest: Bad Prefix: %q", prefix)
+	}
+
+	now := time.Now()
+	locked := !receiver.frozen.Load()
+
+	var candidates []string
+	for _, index := range parsed.shards() {
+		candidates = append(candidates, receiver.shards[index].find(parsed, now, locked)...)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", PrefixNotFound{Prefix:prefix}
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", AmbiguousPrefix{Prefix:prefix, Candidates:candidates}
+	}
+}
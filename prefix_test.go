@@ -0,0 +1,228 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"testing"
+)
+
+func TestSHA1Resolve(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	var hexadecimals []string
+	for i := 0; i < 256; i++ {
+		digest, _ := mem.Store([]byte(fmt.Sprintf("content #%d", i)))
+		hexadecimals = append(hexadecimals, hex.EncodeToString(digest[:]))
+	}
+	sort.Strings(hexadecimals)
+
+	matching := func(prefix string) []string {
+		var a []string
+		for _, hexadecimal := range hexadecimals {
+			if strings.HasPrefix(hexadecimal, strings.ToLower(prefix)) {
+				a = append(a, hexadecimal)
+			}
+		}
+		return a
+	}
+
+	var missing string
+	for i := 0; i < 0x10000; i++ {
+		if prefix := fmt.Sprintf("%04x", i); len(matching(prefix)) < 1 {
+			missing = prefix
+			break
+		}
+	}
+
+	tests := []struct{
+		Prefix string
+	}{
+		{Prefix: hexadecimals[0][:8]},
+		{Prefix: hexadecimals[100][:7]},
+		{Prefix: strings.ToUpper(hexadecimals[200][:10])},
+		{Prefix: hexadecimals[255]},
+		{Prefix: hexadecimals[17][:1]},
+		{Prefix: hexadecimals[17][:2]},
+		{Prefix: missing},
+	}
+
+	for testNumber, test := range tests {
+
+		expected := matching(test.Prefix)
+
+		digest, err := mem.Resolve(test.Prefix)
+
+		switch len(expected) {
+		case 0:
+			if _, casted := err.(memdigest.PrefixNotFound); !casted {
+				t.Errorf("For test #%d, expected a memdigest.PrefixNotFound error, but actually got: (%T) %v", testNumber, err, err)
+				t.Logf("PREFIX: %q", test.Prefix)
+				continue
+			}
+		case 1:
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				t.Logf("PREFIX: %q", test.Prefix)
+				continue
+			}
+			if expected, actual := expected[0], hex.EncodeToString(digest[:]); expected != actual {
+				t.Errorf("For test #%d, the resolved digest was not what was expected.", testNumber)
+				t.Logf("PREFIX:   %q", test.Prefix)
+				t.Logf("EXPECTED: %s", expected)
+				t.Logf("ACTUAL:   %s", actual)
+				continue
+			}
+		default:
+			ambiguous, casted := err.(memdigest.AmbiguousPrefix)
+			if !casted {
+				t.Errorf("For test #%d, expected a memdigest.AmbiguousPrefix error, but actually got: (%T) %v", testNumber, err, err)
+				t.Logf("PREFIX: %q", test.Prefix)
+				continue
+			}
+
+			var actual []string
+			for _, candidate := range ambiguous.Candidates {
+				actual = append(actual, hex.EncodeToString([]byte(candidate)))
+			}
+
+			if expected, actual := strings.Join(expected, " "), strings.Join(actual, " "); expected != actual {
+				t.Errorf("For test #%d, the candidates were not what was expected.", testNumber)
+				t.Logf("PREFIX:   %q", test.Prefix)
+				t.Logf("EXPECTED: %s", expected)
+				t.Logf("ACTUAL:   %s", actual)
+				continue
+			}
+		}
+	}
+}
+
+func TestSHA1ResolveAfterChange(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	digestApple, _ := mem.Store([]byte("apple"))
+	prefix := hex.EncodeToString(digestApple[:4])
+
+	if _, err := mem.Resolve(prefix); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	mem.Delete(digestApple[:])
+
+	if _, err := mem.Resolve(prefix); nil == err {
+		t.Errorf("Expected an error resolving the prefix of deleted content, but did not actually get one.")
+	}
+
+	mem.Store([]byte("apple"))
+
+	if _, err := mem.Resolve(prefix); nil != err {
+		t.Errorf("Did not expect an error resolving the prefix of re-stored content, but actually got one: (%T) %q", err, err)
+	}
+}
+
+func TestSHA1ResolveInterleaved(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	// (This builds the index, so that everything after this has to keep it up to date.)
+	mem.Resolve("0")
+
+	stored := map[string]bool{}
+
+	for i := 0; i < 512; i++ {
+		content := []byte(fmt.Sprintf("content #%d", i))
+		hexadecimal := fmt.Sprintf("%x", sha1.Sum(content))
+
+		switch i % 4 {
+		case 0, 1:
+			mem.Store(content)
+			stored[hexadecimal] = true
+		case 2:
+			// Stored, and then removed again, by expiring and being swept.
+			mem.StoreWithTTL(content, time.Nanosecond)
+			time.Sleep(time.Nanosecond)
+			mem.Sweep()
+		case 3:
+			// Stored, and then deleted.
+			digest, _ := mem.Store(content)
+			mem.Delete(digest[:])
+		}
+
+		for hexadecimal := range stored {
+			actual, err := mem.Resolve(hexadecimal)
+			if nil != err {
+				t.Errorf("For #%d, did not expect an error resolving stored content, but actually got one: (%T) %q", i, err, err)
+				return
+			}
+			if expected := hexadecimal; expected != fmt.Sprintf("%x", actual) {
+				t.Errorf("For #%d, the resolved digest was not what was expected.", i)
+				t.Logf("EXPECTED: %s", expected)
+				t.Logf("ACTUAL:   %x", actual)
+				return
+			}
+		}
+	}
+
+	for i := 0; i < 512; i++ {
+		content := []byte(fmt.Sprintf("content #%d", i))
+		hexadecimal := fmt.Sprintf("%x", sha1.Sum(content))
+
+		if _, err := mem.Resolve(hexadecimal); stored[hexadecimal] != (nil == err) {
+			t.Errorf("For #%d, whether the digest resolved was not what was expected.", i)
+			t.Logf("EXPECTED: %t", stored[hexadecimal])
+			t.Logf("ERROR: (%T) %v", err, err)
+		}
+	}
+}
+
+func TestSHA1ResolveBadPrefix(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("apple"))
+
+	tests := []string{
+		"",
+		"xyz",
+		"d0be2dc4-",
+		strings.Repeat("0", 2*sha1.Size+1),
+	}
+
+	for testNumber, prefix := range tests {
+		if _, err := mem.Resolve(prefix); nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			t.Logf("PREFIX: %q", prefix)
+		}
+	}
+}
+
+func TestSHA1OpenLocationAbbreviated(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("apple"))
+	mem.Store([]byte("Hello world!"))
+
+	content, err := mem.OpenLocation("memdigest:sha-1:hexadecimal(d3486ae9)/0")
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	p, _ := io.ReadAll(io.NewSectionReader(content, 0, 1<<20))
+	if expected, actual := "Hello world!", string(p); expected != actual {
+		t.Errorf("The content that was actually opened was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	if _, err := mem.OpenLocation("memdigest:sha-1:hexadecimal(ffff)/0"); nil == err {
+		t.Errorf("Expected an error opening a location whose abbreviated digest matches nothing, but did not actually get one.")
+	}
+}
@@ -0,0 +1,78 @@
+package memdigest
+
+import (
+	"iter"
+	"sort"
+	"time"
+)
+
+// item is a piece of content, and the digest it is stored under.
+type item struct {
+	key string
+	value string
+}
+
+// items returns a consistent view of all the (unexpired) content in the store, sorted by digest.
+//
+// All the shards are read-locked at the same time while the view is made, so the view is of the store at a single point in time.
+func (receiver *Store) items() []item {
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.RLock()
+	}
+
+	now := time.Now()
+
+	var items []item
+	for i := range receiver.shards {
+		for key, e := range receiver.shards[i].data {
+			if e.expired(now) && e.pins < 1 {
+				continue
+			}
+
+			items = append(items, item{key:key, value:e.value})
+		}
+	}
+
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.RUnlock()
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key < items[j].key
+	})
+
+	return items
+}
+
+// Range calls ‘fn’ with the digest and length of each piece of content in the store, in order of digest, until ‘fn’ returns false.
+//
+// Range iterates over a consistent view of the store, taken when Range is called.
+// It is safe to call Range concurrently with Store (and with anything else); and ‘fn’ may itself call the store's methods.
+//
+// The digest is in binary form, not hexadecimal.
+func (receiver *Store) Range(fn func(digest string, size int) bool) {
+	if nil == receiver {
+		return
+	}
+
+	for _, item := range receiver.items() {
+		if !fn(item.key, len(item.value)) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the digest and length of each piece of content in the store, in order of digest.
+//
+// Like Range, All iterates over a consistent view of the store, taken when the iteration starts.
+//
+// Example
+//
+//	for digest, size := range mem.All() {
+//		fmt.Printf("%x %d\n", digest, size)
+//	}
+func (receiver *Store) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		receiver.Range(yield)
+	}
+}
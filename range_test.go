@@ -0,0 +1,98 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"fmt"
+	"sync"
+
+	"testing"
+)
+
+func TestSHA1Range(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	expected := map[string]int{}
+	for _, content := range []string{"apple", "BANANA", "Cherry", "dATE"} {
+		digest, err := mem.Store([]byte(content))
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+		expected[fmt.Sprintf("%x", digest)] = len(content)
+	}
+
+	{
+		actual := map[string]int{}
+		var previous string
+
+		mem.Range(func(digest [20]byte, size int) bool {
+			hexadecimal := fmt.Sprintf("%x", digest)
+			if hexadecimal <= previous {
+				t.Errorf("Expected digests in order, but actually got %q after %q.", hexadecimal, previous)
+			}
+			previous = hexadecimal
+
+			actual[hexadecimal] = size
+			return true
+		})
+
+		if fmt.Sprint(expected) != fmt.Sprint(actual) {
+			t.Errorf("What Range iterated over was not what was expected.")
+			t.Logf("EXPECTED: %v", expected)
+			t.Logf("ACTUAL:   %v", actual)
+		}
+	}
+
+	{
+		actual := map[string]int{}
+
+		for digest, size := range mem.All() {
+			actual[fmt.Sprintf("%x", digest)] = size
+		}
+
+		if fmt.Sprint(expected) != fmt.Sprint(actual) {
+			t.Errorf("What All iterated over was not what was expected.")
+			t.Logf("EXPECTED: %v", expected)
+			t.Logf("ACTUAL:   %v", actual)
+		}
+	}
+
+	{
+		var count int
+		for range mem.All() {
+			count++
+			break
+		}
+
+		if expected, actual := 1, count; expected != actual {
+			t.Errorf("Expected iteration to stop early, but it actually didn't.")
+		}
+	}
+}
+
+func TestSHA1RangeConcurrentWithStore(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	var waitgroup sync.WaitGroup
+
+	waitgroup.Add(1)
+	go func() {
+		defer waitgroup.Done()
+
+		for i := 0; i < 1000; i++ {
+			mem.Store([]byte(fmt.Sprintf("content #%d", i)))
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		for digest := range mem.All() {
+			if _, found := mem.Load(digest[:]); !found {
+				t.Errorf("Expected content that was iterated over to exist, but it actually didn't: %x", digest)
+			}
+		}
+	}
+
+	waitgroup.Wait()
+}
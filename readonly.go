@@ -0,0 +1,75 @@
+package memdigest
+
+// Freeze makes the store read-only.
+//
+// After Freeze returns, Create, Store, Insert, StoreWithTTL, StoreFrom, StoreFromN, ReadFrom, ImportTar, OpenLog, Delete, Pin, Unpin,
+// and the Close of a Writer, all return ErrReadOnly; and nothing is removed by eviction, by expiry, by Sweep, or by Collect. (Expired content is still not returned, though.)
+//
+// Since the content can no longer change, readers (Load, Open, OpenLocation, and Resolve) no longer take any locks.
+//
+// A store cannot be unfrozen. (Unmount, which empties the store, must not be called while it is being read.)
+//
+// A store can also be frozen when it is mounted, by giving the Mounter the "read-only" argument.
+//
+// Example
+//
+//	// Preload the assets.
+//	// ...
+//
+//	mem.Freeze()
+func (receiver *Store) Freeze() {
+	if nil == receiver {
+		return
+	}
+
+	// (The journal's lock is taken too, so that nothing is appended to the log, while the store is being frozen, that then cannot be stored.)
+	receiver.journal.mutex.Lock()
+	defer receiver.journal.mutex.Unlock()
+
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.Lock()
+	}
+
+	// Build the prefix index now, since a frozen store does not take the locks it would need to build it later.
+	for i := range receiver.shards {
+		receiver.shards[i].buildIndex()
+		receiver.shards[i].frozen = true
+	}
+
+	receiver.frozen.Store(true)
+
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.Unlock()
+	}
+}
+
+// Frozen returns whether the store is read-only (see Freeze).
+func (receiver *Store) Frozen() bool {
+	if nil == receiver {
+		return false
+	}
+
+	return receiver.frozen.Load()
+}
+
+// entry returns what is stored under ‘key’ (without taking a lock, if the store is frozen).
+func (receiver *Store) entry(key string) *entry {
+	shard := receiver.shard(key)
+
+	if receiver.frozen.Load() {
+		return shard.data[key]
+	}
+
+	return shard.get(key)
+}
+
+// entryBytes is like entry, but for a ‘key’ that is a []byte.
+func (receiver *Store) entryBytes(key []byte) *entry {
+	shard := &receiver.shards[shardIndex(key[0])]
+
+	if receiver.frozen.Load() {
+		return shard.data[string(key)]
+	}
+
+	return shard.getBytes(key)
+}
@@ -0,0 +1,247 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+	"github.com/reiver/go-digestfs"
+
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"testing"
+)
+
+func TestSHA1Freeze(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	digest, _ := mem.Store([]byte("apple"))
+
+	if mem.Frozen() {
+		t.Errorf("Did not expect the store to be frozen before Freeze was called, but it actually was.")
+	}
+
+	mem.Freeze()
+
+	if !mem.Frozen() {
+		t.Errorf("Expected the store to be frozen after Freeze was called, but it actually wasn't.")
+	}
+
+	tests := []struct{
+		Name string
+		Func func() error
+	}{
+		{
+			Name: "Store",
+			Func: func() error { _, err := mem.Store([]byte("BANANA")); return err },
+		},
+		{
+			Name: "Store (already stored)",
+			Func: func() error { _, err := mem.Store([]byte("apple")); return err },
+		},
+		{
+			Name: "Create",
+			Func: func() error { _, _, err := mem.Create([]byte("BANANA")); return err },
+		},
+		{
+			Name: "Delete",
+			Func: func() error { _, err := mem.Delete(digest[:]); return err },
+		},
+		{
+			Name: "Pin",
+			Func: func() error { return mem.Pin(digest[:]) },
+		},
+		{
+			Name: "Writer",
+			Func: func() error { w := mem.Writer(); w.Write([]byte("BANANA")); return w.Close() },
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		err := test.Func()
+		if !errors.Is(err, memdigest.ErrReadOnly) {
+			t.Errorf("For test #%d, expected the error to be memdigest.ErrReadOnly, but it actually wasn't.", testNumber)
+			t.Logf("NAME: %s", test.Name)
+			t.Logf("ERROR: (%T) %v", err, err)
+			continue
+		}
+	}
+
+	if actual, found := mem.Load(digest[:]); !found || "apple" != actual {
+		t.Errorf("Expected the content to still be loadable after Freeze, but it actually wasn't.")
+		t.Logf("FOUND:  %t", found)
+		t.Logf("ACTUAL: %q", actual)
+	}
+
+	if expected, actual := 1, mem.Stats().Blobs; expected != actual {
+		t.Errorf("The number of blobs after Freeze was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
+
+func TestSHA1FreezeConcurrentReaders(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	var digests [][sha1.Size]byte
+	for i := 0; i < 100; i++ {
+		digest, _ := mem.Store([]byte(fmt.Sprintf("asset #%d", i)))
+		digests = append(digests, digest)
+	}
+
+	mem.Freeze()
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			for _, digest := range digests {
+				if _, found := mem.Load(digest[:]); !found {
+					t.Errorf("Expected content to be found, but it actually wasn't: %x", digest)
+				}
+				if _, err := mem.Resolve(fmt.Sprintf("%x", digest[:8])); nil != err {
+					t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+}
+
+func TestSHA1FreezeConcurrentWriters(t *testing.T) {
+
+	// (This is most useful when run with the race detector; i.e., "go test -race".)
+	for round := 0; round < 5; round++ {
+
+		var mem memdigest.SHA1
+
+		var contents [][]byte
+		var digests [][sha1.Size]byte
+		for i := 0; i < 4; i++ {
+			content := []byte(fmt.Sprintf("content #%d", i))
+
+			contents = append(contents, content)
+			digests = append(digests, sha1.Sum(content))
+		}
+
+		var writers sync.WaitGroup
+		var readers sync.WaitGroup
+		var done atomic.Bool
+
+		// The writers keep storing and deleting until after the store is frozen, so that some of them are racing with Freeze.
+		for _, content := range contents {
+			writers.Add(1)
+			go func(content []byte) {
+				defer writers.Done()
+
+				for !mem.Frozen() {
+					digest, err := mem.Store(content)
+					if nil != err && !errors.Is(err, memdigest.ErrReadOnly) {
+						t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+						return
+					}
+
+					mem.Pin(digest[:])
+					mem.Unpin(digest[:])
+					mem.Delete(digest[:])
+				}
+			}(content)
+		}
+
+		// The readers keep loading what the writers are writing, until the writers are done.
+		for i := 0; i < 2; i++ {
+			readers.Add(1)
+			go func() {
+				defer readers.Done()
+
+				for !done.Load() {
+					for _, digest := range digests {
+						mem.Load(digest[:])
+					}
+				}
+			}()
+		}
+
+		time.Sleep(time.Millisecond)
+		mem.Freeze()
+		expected := mem.Stats().Blobs
+
+		writers.Wait()
+		done.Store(true)
+		readers.Wait()
+
+		if actual := mem.Stats().Blobs; expected != actual {
+			t.Errorf("Did not expect what is stored to change after Freeze returned, but it actually did.")
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+	}
+}
+
+func TestSHA1MountReadOnly(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("apple"))
+	defer mem.Unmount()
+
+	var mountpoint digestfs.MountPoint
+	if err := mountpoint.Mount("memdigest.SHA1", &mem, "read-only"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if !mem.Frozen() {
+		t.Errorf("Expected the store to be frozen after being mounted read-only, but it actually wasn't.")
+	}
+
+	var other memdigest.SHA1
+	if err := mountpoint.Mount("memdigest.SHA1", &other, "read-write"); nil == err {
+		t.Errorf("Expected an error from mounting with an unknown option, but did not actually get one.")
+	}
+}
+
+// benchmarkLoadWithLimits measures the throughput of concurrent Loads of a store that has limits (and so keeps track of what was least recently used).
+func benchmarkLoadWithLimits(b *testing.B, freeze bool) {
+
+	var mem memdigest.SHA1
+
+	mem.SetLimits(0, 1000)
+
+	var digests [][sha1.Size]byte
+	for i := 0; i < 100; i++ {
+		digest, _ := mem.Store([]byte(fmt.Sprintf("asset #%d", i)))
+		digests = append(digests, digest)
+	}
+
+	if freeze {
+		mem.Freeze()
+	}
+
+	b.SetParallelism(8)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			digest := digests[i % len(digests)]
+			if _, found := mem.Load(digest[:]); !found {
+				b.Error("Expected value to exist for the SHA-1 digest.")
+			}
+		}
+	})
+}
+
+// BenchmarkSHA1LoadWithLimits measures Load of a store with limits, which has to take the lock of its record of what was least recently used.
+func BenchmarkSHA1LoadWithLimits(b *testing.B) {
+	benchmarkLoadWithLimits(b, false)
+}
+
+// BenchmarkFrozenSHA1LoadWithLimits measures Load of a frozen store with limits, which does not take any locks.
+func BenchmarkFrozenSHA1LoadWithLimits(b *testing.B) {
+	benchmarkLoadWithLimits(b, true)
+}
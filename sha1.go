@@ -1,13 +1,10 @@
 package memdigest
 
 import (
-	"github.com/reiver/go-digestfs/driver"
-
 	"crypto/sha1"
-	"encoding/hex"
-	"fmt"
-	"strings"
-	"sync"
+	"io"
+	"iter"
+	"time"
 )
 
 const (
@@ -15,149 +12,120 @@ const (
 )
 
 func init() {
-	const name string = "memdigest.SHA1"
-
-	var mounter digestfs_driver.Mounter = digestfs_driver.MounterFunc(func(args ...interface{}) (digestfs_driver.MountPoint, error){
-		if expected, actual := 1, len(args); expected != actual {
-			return nil, fmt.Errorf("memdigest: Wrong Number Of Arguments: expected %d, but actually got %d", expected, actual)
-		}
-
-		arg0 := args[0]
-
-		mem, casted := arg0.(*SHA1)
-		if !casted {
-			return nil, fmt.Errorf("memdigest: Wrong Type: expected *memdigest.SHA1, but actually got %T", arg0)
-		}
-
-		return mem, nil
-	})
-
-	digestfs_driver.Registry.Register(mounter, name)
+	registerFixed[SHA1]("memdigest.SHA1")
 }
 
+// SHA1 is an in memory content-addressable storage (CAS) that uses SHA-1.
+//
+// The zero value of SHA1 is ready to use.
+//
+// Besides the methods here (which return SHA-1 digests as [sha1.Size]byte), SHA1 has the methods that every fixed-algorithm store has
+// (ex: Load, Delete, Pin, Freeze, Stats, and Snapshot). It can be mounted with digestfs using the name "memdigest.SHA1".
 type SHA1 struct {
-	mutex sync.RWMutex
-	data map[[sha1.Size]byte]string
+	fixed[sha1Algorithm]
+}
+
+type sha1Algorithm struct{}
+
+func (sha1Algorithm) algorithmName() string {
+	return algorithmSHA1
 }
 
-// Create makes *memdigest.SHA1 fit the digestfs_driver.MountPoint interface.
+// All returns an iterator over the SHA-1 digest and length of each piece of content in the store, in order of digest.
 //
-// Create is very similar to Store, in that it tores ‘content’ and returns the SHA-1 digest of ‘content’.
+// All iterates over a consistent view of the store, taken when the iteration starts, and is safe to use concurrently with Store.
 //
 // Example
 //
-// Here is an example of it being used:
-//
 //	var mem *memdigest.SHA1
 //	
 //	// ...
 //	
-//	var content []byte = []byte("The request has been fulfilled and resulted in a new resource being created.")
-//	
-//	// ...
-//	
-//	algorithm, digest, err := mem.Create(content)
-//
-// The returned digest is in binary form, not hexadecimal.
-//
-// In the case of our example, it will be:
-//
-//	[64]byte{0x0c, 0xe9, 0xff, 0x3b, 0x12, 0xaf, 0xdb, 0x31, 0x61, 0x75, 0x1e, 0x3a, 0xb4, 0x49, 0x87, 0x62, 0x95, 0x23, 0x63, 0x3d}
-//
-// If you want to convert it to hexadecimal, you can do so with code such as:
-//
-//	hexadecimalDigest := fmt.Sprintf("%x", digest)
-//
-// Which will return the string:
-//
-//	"0ce9ff3b12afdb3161751e3ab44987629523633d"
+//	for digest, size := range mem.All() {
+//		fmt.Printf("%x %d\n", digest, size)
+//	}
+func (receiver *SHA1) All() iter.Seq2[[sha1.Size]byte, int] {
+	return func(yield func([sha1.Size]byte, int) bool) {
+		receiver.Range(yield)
+	}
+}
+
+// Insert stores ‘content’ and returns the SHA-1 digest of ‘content’, and whether ‘content’ was newly inserted.
 //
-// More typically though, this would be use Create through package digestfs.
-func (receiver *SHA1) Create(p []byte) (algorithm string, digest string, err error) {
+// If ‘content’ was already stored, then Insert returns without copying ‘content’ again, and ‘inserted’ is false.
+func (receiver *SHA1) Insert(content []byte) (digest [sha1.Size]byte, inserted bool, err error) {
 	if nil == receiver {
-		return algorithmSHA1, "", errNilReceiver
+		return [sha1.Size]byte{}, false, errNilReceiver
 	}
 
-	digest20, err := receiver.Store(p)
+	key, inserted, err := receiver.storage().Insert(content)
 	if nil != err {
-		return algorithmSHA1, "", err
+		return [sha1.Size]byte{}, false, err
 	}
 
-	return algorithmSHA1, string(digest20[:]), nil
+	copy(digest[:], key)
+
+	return digest, inserted, nil
 }
 
-func (receiver *SHA1) Load(digest []byte) (string, bool) {
+// OnEvict sets the function that is called (with the SHA-1 digest and length of the content) whenever content is evicted.
+func (receiver *SHA1) OnEvict(fn func(digest [sha1.Size]byte, size int)) {
 	if nil == receiver {
-		return "", false
-	}
-
-	if sha1.Size != len(digest) {
-		return "", false
+		return
 	}
 
-	receiver.mutex.RLock()
-	defer receiver.mutex.RUnlock()
-
-	data := receiver.data
-	if nil == data {
-		return "", false
+	if nil == fn {
+		receiver.storage().OnEvict(nil)
+		return
 	}
 
-	var key [sha1.Size]byte
-	copy(key[:], digest)
-
-	value, found := data[key]
-	if !found {
-		return "", false
-	}
+	receiver.storage().OnEvict(func(digest string, size int) {
+		var key [sha1.Size]byte
+		copy(key[:], digest)
 
-	return value, true
+		fn(key, size)
+	})
 }
 
-func (receiver *SHA1) Open(algorithm string, digest string) (digestfs_driver.Content, error) {
+// Range calls ‘fn’ with the SHA-1 digest and length of each piece of content in the store, in order of digest, until ‘fn’ returns false.
+//
+// Range iterates over a consistent view of the store, taken when Range is called, and is safe to call concurrently with Store.
+func (receiver *SHA1) Range(fn func(digest [sha1.Size]byte, size int) bool) {
 	if nil == receiver {
-		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
-	}
-
-	if algorithmSHA1 != algorithm {
-		return nil, digestfs_driver.ErrUnsupportedAlgorithm(algorithm)
-	}
-
-	if sha1.Size != len(digest) {
-		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+		return
 	}
 
-	var d [sha1.Size]byte
+	receiver.storage().Range(func(digest string, size int) bool {
+		var key [sha1.Size]byte
+		copy(key[:], digest)
 
-	copy(d[:], digest)
-
-	value, found := receiver.Load(d[:])
-	if !found {
-		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
-	}
-
-	return digestfs_driver.StringContent(value), nil
+		return fn(key, size)
+	})
 }
 
-// OpenLocation makes *memdigest.SHA1 fit the digestfs_driver.MountPoint interface.
-func (receiver *SHA1) OpenLocation(location string) (digestfs_driver.Content, error) {
-	const prefix string = "memdigest:sha-1:hexadecimal("
-	const suffix string = ")/0"
+// Resolve returns the digest of the stored content whose hexadecimal digest begins with ‘prefix’, if there is exactly one.
+//
+// If more than one digest begins with ‘prefix’, then Resolve returns a memdigest.AmbiguousPrefix error, which lists them.
+// If none do, then Resolve returns a memdigest.PrefixNotFound error.
+//
+// Example
+//
+//	digest, err := mem.Resolve("70cc03f6")
+func (receiver *SHA1) Resolve(prefix string) ([sha1.Size]byte, error) {
+	var digest [sha1.Size]byte
 
-	if !strings.HasPrefix(location, prefix) {
-		return nil, digestfs_driver.ErrBadLocation(location)
-	}
-	if !strings.HasSuffix(location, suffix) {
-		return nil, digestfs_driver.ErrBadLocation(location)
+	if nil == receiver {
+		return digest, errNilReceiver
 	}
-	digestHexadecimal := location[len(prefix):len(location)-len(suffix)]
 
-	digest, err := hex.DecodeString(digestHexadecimal)
+	resolved, err := receiver.storage().Resolve(prefix)
 	if nil != err {
-		return nil, digestfs_driver.ErrBadLocation(location)
+		return digest, err
 	}
 
-	return receiver.Open(algorithmSHA1, string(digest))
+	copy(digest[:], resolved)
+
+	return digest, nil
 }
 
 // Store stores ‘content’ and returns the SHA-1 digest of ‘content’.
@@ -194,42 +162,79 @@ func (receiver *SHA1) Store(content []byte) ([sha1.Size]byte, error) {
 		return [sha1.Size]byte{}, errNilReceiver
 	}
 
-	receiver.mutex.Lock()
-	defer receiver.mutex.Unlock()
+	digest, err := receiver.storage().Store(content)
+	if nil != err {
+		return [sha1.Size]byte{}, err
+	}
+
+	var key [sha1.Size]byte
+	copy(key[:], digest)
+
+	return key, nil
+}
+
+// StoreFrom stores the content read from ‘r’ (until io.EOF), and returns the SHA-1 digest of that content.
+//
+// Unlike Store, StoreFrom does not need the whole content to be in memory before it is stored.
+// The content is hashed while it is being copied into the store's own buffer.
+func (receiver *SHA1) StoreFrom(r io.Reader) ([sha1.Size]byte, error) {
+	if nil == receiver {
+		return [sha1.Size]byte{}, errNilReceiver
+	}
+
+	digest, err := receiver.storage().StoreFrom(r)
+	if nil != err {
+		return [sha1.Size]byte{}, err
+	}
+
+	var key [sha1.Size]byte
+	copy(key[:], digest)
+
+	return key, nil
+}
 
-	if nil == receiver.data {
-		receiver.data = map[[sha1.Size]byte]string{}
+// StoreFromN stores the next ‘n’ bytes read from ‘r’, and returns the SHA-1 digest of them.
+//
+// If ‘r’ ends before ‘n’ bytes are read, then nothing is stored, and io.ErrUnexpectedEOF is returned.
+func (receiver *SHA1) StoreFromN(r io.Reader, n int64) ([sha1.Size]byte, error) {
+	if nil == receiver {
+		return [sha1.Size]byte{}, errNilReceiver
 	}
 
-	key := sha1.Sum(content)
+	digest, err := receiver.storage().StoreFromN(r, n)
+	if nil != err {
+		return [sha1.Size]byte{}, err
+	}
 
-	receiver.data[key] = string(content)
+	var key [sha1.Size]byte
+	copy(key[:], digest)
 
 	return key, nil
 }
 
-// Unmount makes *memdigest.SHA1 fit the digestfs_driver.MountPoint interface.
+// StoreWithTTL stores ‘content’ and returns the SHA-1 digest of ‘content’.
 //
-// Unmount will never return an error, but will (conceptually) remove all content it was previously storing.
+// The content expires ‘ttl’ after it was stored, or after it was last read, whichever is later.
 //
 // Example
 //
-// Here is an example of it being used:
-//
 //	var mem *memdigest.SHA1
 //	
 //	// ...
 //	
-//	err := mem.Unmount()
-func (receiver *SHA1) Unmount() error {
+//	digest, err := mem.StoreWithTTL(content, 5 * time.Minute)
+func (receiver *SHA1) StoreWithTTL(content []byte, ttl time.Duration) ([sha1.Size]byte, error) {
 	if nil == receiver {
-		return nil
+		return [sha1.Size]byte{}, errNilReceiver
 	}
 
-	receiver.mutex.Lock()
-	defer receiver.mutex.Unlock()
+	digest, err := receiver.storage().StoreWithTTL(content, ttl)
+	if nil != err {
+		return [sha1.Size]byte{}, err
+	}
 
-	receiver.data = nil
+	var key [sha1.Size]byte
+	copy(key[:], digest)
 
-	return nil
+	return key, nil
 }
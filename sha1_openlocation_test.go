@@ -3,6 +3,9 @@ package memdigest_test
 import (
 	"github.com/reiver/go-memdigest"
 
+	"github.com/reiver/go-digestfs"
+	"github.com/reiver/go-digestfs/driver"
+
 	"bytes"
 	"encoding/hex"
 	"io"
@@ -139,3 +142,237 @@ func TestSHA1OpenLocation(t *testing.T) {
 		}
 	}
 }
+
+func TestSHA1LocationRoundTrip(t *testing.T) {
+
+	tests := []struct{
+		Content string
+		ExpectedLocation string
+	}{
+		{
+			Content: "Hello world!",
+			ExpectedLocation: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/0",
+		},
+		{
+			Content: "😏😐👾🤖😈",
+			ExpectedLocation: "memdigest:sha-1:hexadecimal(1af2b71ae04ddb01cc36cc615e64c950a50b04ff)/0",
+		},
+		{
+			Content: "",
+			ExpectedLocation: "memdigest:sha-1:hexadecimal(da39a3ee5e6b4b0d3255bfef95601890afd80709)/0",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var mem memdigest.SHA1
+
+		digest, err := mem.Store([]byte(test.Content))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		location, err := mem.Location(digest[:])
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedLocation, location; expected != actual {
+			t.Errorf("For test #%d, the actual location was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		content, err := mem.OpenLocation(location)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			t.Logf("Location: %q", location)
+			continue
+		}
+
+		contentBytes, err := ioutil.ReadAll(io.NewSectionReader(content, 0, int64(content.Len())))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Content, string(contentBytes); expected != actual {
+			t.Errorf("For test #%d, the actual content was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func TestSHA1OpenLocationOffset(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("Hello world!"))
+
+	tests := []struct{
+		Location string
+		Expected string
+		ExpectedError bool
+	}{
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/0",
+			Expected: "Hello world!",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/6",
+			Expected: "world!",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/12",
+			Expected: "",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/13",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/06",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/-1",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/0-5",
+			Expected: "Hello",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/6-11",
+			Expected: "world",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/6-12",
+			Expected: "world!",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/3-3",
+			Expected: "",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/6-13",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/6-5",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/6-",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea797094475802)/-6",
+			ExpectedError: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9)/6-11",
+			Expected: "world",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		content, err := mem.OpenLocation(test.Location)
+		if test.ExpectedError {
+			if nil == err {
+				t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+				t.Logf("Location: %q", test.Location)
+			}
+			continue
+		}
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			t.Logf("Location: %q", test.Location)
+			continue
+		}
+
+		contentBytes, err := ioutil.ReadAll(io.NewSectionReader(content, 0, int64(content.Len())))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, string(contentBytes); expected != actual {
+			t.Errorf("For test #%d, the actual content was not what was expected.", testNumber)
+			t.Logf("Location: %q", test.Location)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func TestSHA1OpenLocationErrors(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("Hello world!"))
+
+	tests := []struct{
+		Location string
+		ExpectedContentNotFound bool
+	}{
+		{
+			Location: "memdigest:sha-1:hexadecimal()/0",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9136e7856bc42212385ea79709447580200)/0",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(d3486ae9zz)/0",
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(ff)/0",
+			ExpectedContentNotFound: true,
+		},
+		{
+			Location: "memdigest:sha-1:hexadecimal(ffffffffffffffffffffffffffffffffffffffff)/0",
+			ExpectedContentNotFound: true,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		content, err := mem.OpenLocation(test.Location)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			t.Logf("Location: %q", test.Location)
+			continue
+		}
+		if nil != content {
+			t.Errorf("For test #%d, expected nil content, but actually wasn't: %#v", testNumber, content)
+			continue
+		}
+
+		if test.ExpectedContentNotFound {
+			switch err.(type) {
+			case digestfs.ContentNotFound:
+				// Nothing here.
+			default:
+				t.Errorf("For test #%d, expected error to be ContentNotFound, but actually wasn't: (%T) %q", testNumber, err, err)
+				t.Logf("Location: %q", test.Location)
+			}
+			continue
+		}
+
+		if expected, actual := digestfs_driver.ErrBadLocation(test.Location).Error(), err.Error(); expected != actual {
+			t.Errorf("For test #%d, expected the error to be a bad location error, but actually wasn't: (%T) %q", testNumber, err, err)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sync"
 
 	"testing"
 )
@@ -293,3 +294,130 @@ func TestSHA1(t *testing.T) {
 		}
 	}
 }
+
+func TestSHA1Insert(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	content := []byte("Hello world!")
+
+	for i, expectedInserted := range []bool{true, false, false} {
+		digest, inserted, err := mem.Insert(content)
+		if nil != err {
+			t.Errorf("For insert #%d, did not expect an error, but actually got one: (%T) %q", i, err, err)
+			continue
+		}
+
+		if expected, actual := "d3486ae9136e7856bc42212385ea797094475802", fmt.Sprintf("%x", digest); expected != actual {
+			t.Errorf("For insert #%d, the SHA-1 that was actually gotten was not what was expected.", i)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		if expected, actual := expectedInserted, inserted; expected != actual {
+			t.Errorf("For insert #%d, expected inserted to be %t, but actually was %t.", i, expected, actual)
+			continue
+		}
+	}
+}
+
+// hashInsideLockSHA1 is how memdigest.SHA1 used to store content, before Store did its hashing (and copying) outside of the lock:
+// the hashing, and the copying, happen while the (write) lock is held.
+//
+// It is only here so that BenchmarkSHA1LoadDuringLargeStore can be compared against BenchmarkHashInsideLockSHA1LoadDuringLargeStore.
+type hashInsideLockSHA1 struct {
+	mutex sync.RWMutex
+	data map[[sha1.Size]byte]string
+}
+
+func (receiver *hashInsideLockSHA1) Load(digest []byte) (string, bool) {
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	var key [sha1.Size]byte
+	copy(key[:], digest)
+
+	value, found := receiver.data[key]
+
+	return value, found
+}
+
+func (receiver *hashInsideLockSHA1) Store(content []byte) ([sha1.Size]byte, error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if nil == receiver.data {
+		receiver.data = map[[sha1.Size]byte]string{}
+	}
+
+	key := sha1.Sum(content)
+	if _, found := receiver.data[key]; !found {
+		receiver.data[key] = string(content)
+	}
+
+	return key, nil
+}
+
+// benchmarkLoadDuringLargeStore measures the throughput of Load while other goroutines are
+// continually storing large (1 MiB) blobs.
+func benchmarkLoadDuringLargeStore(b *testing.B, mem loadStorer) {
+
+	digest, err := mem.Store([]byte("Hello world!"))
+	if nil != err {
+		b.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	done := make(chan struct{})
+	var waitgroup sync.WaitGroup
+
+	for writer := 0; writer < 4; writer++ {
+		waitgroup.Add(1)
+		go func(writer int) {
+			defer waitgroup.Done()
+
+			blob := make([]byte, 1<<20)
+			blob[0] = byte(writer)
+
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				blob[1] = byte(i % 16)
+				mem.Store(blob)
+			}
+		}(writer)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, found := mem.Load(digest[:]); !found {
+				b.Error("Expected value to exist for the SHA-1 digest.")
+			}
+		}
+	})
+
+	b.StopTimer()
+
+	close(done)
+	waitgroup.Wait()
+}
+
+// BenchmarkSHA1LoadDuringLargeStore measures the throughput of Load while other goroutines are
+// continually storing large (1 MiB) blobs.
+//
+// Because Store does its hashing outside of the lock, readers are only blocked for the map insert,
+// rather than for the whole time it takes to hash a large blob. (Compare with BenchmarkHashInsideLockSHA1LoadDuringLargeStore.)
+func BenchmarkSHA1LoadDuringLargeStore(b *testing.B) {
+	benchmarkLoadDuringLargeStore(b, new(memdigest.SHA1))
+}
+
+// BenchmarkHashInsideLockSHA1LoadDuringLargeStore is BenchmarkSHA1LoadDuringLargeStore, but with the hashing (and copying) done inside the lock.
+func BenchmarkHashInsideLockSHA1LoadDuringLargeStore(b *testing.B) {
+	benchmarkLoadDuringLargeStore(b, new(hashInsideLockSHA1))
+}
@@ -0,0 +1,48 @@
+package memdigest
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+)
+
+const (
+	algorithmSHA224 string = "SHA-224"
+	algorithmSHA384 string = "SHA-384"
+	algorithmSHA512 string = "SHA-512"
+	algorithmSHA512_256 string = "SHA-512/256"
+)
+
+func init() {
+	registerStore("memdigest.SHA224", algorithmSHA224)
+	registerStore("memdigest.SHA384", algorithmSHA384)
+	registerStore("memdigest.SHA512", algorithmSHA512)
+	registerStore("memdigest.SHA512_256", algorithmSHA512_256)
+}
+
+// NewSHA224 returns a new *memdigest.Store that uses SHA-224.
+//
+// It can be mounted with digestfs using the name "memdigest.SHA224".
+func NewSHA224() *Store {
+	return NewStore(algorithmSHA224, sha256.Size224, sha256.New224)
+}
+
+// NewSHA384 returns a new *memdigest.Store that uses SHA-384.
+//
+// It can be mounted with digestfs using the name "memdigest.SHA384".
+func NewSHA384() *Store {
+	return NewStore(algorithmSHA384, sha512.Size384, sha512.New384)
+}
+
+// NewSHA512 returns a new *memdigest.Store that uses SHA-512.
+//
+// It can be mounted with digestfs using the name "memdigest.SHA512".
+func NewSHA512() *Store {
+	return NewStore(algorithmSHA512, sha512.Size, sha512.New)
+}
+
+// NewSHA512_256 returns a new *memdigest.Store that uses SHA-512/256.
+//
+// It can be mounted with digestfs using the name "memdigest.SHA512_256".
+func NewSHA512_256() *Store {
+	return NewStore(algorithmSHA512_256, sha512.Size256, sha512.New512_256)
+}
@@ -0,0 +1,255 @@
+package memdigest
+
+import (
+	"crypto/sha256"
+	"io"
+	"iter"
+	"time"
+)
+
+const (
+	algorithmSHA256 string = "SHA-256"
+)
+
+func init() {
+	registerFixed[SHA256]("memdigest.SHA256")
+}
+
+// SHA256 is an in memory content-addressable storage (CAS) that uses SHA-256.
+//
+// The zero value of SHA256 is ready to use.
+//
+// Besides the methods here (which return SHA-256 digests as [sha256.Size]byte, or are only for SHA-256), SHA256 has the methods that every fixed-algorithm store has
+// (ex: Load, Delete, Pin, Freeze, Stats, and Snapshot). It can be mounted with digestfs using the name "memdigest.SHA256".
+type SHA256 struct {
+	fixed[sha256Algorithm]
+}
+
+type sha256Algorithm struct{}
+
+func (sha256Algorithm) algorithmName() string {
+	return algorithmSHA256
+}
+
+// All returns an iterator over the SHA-256 digest and length of each piece of content in the store, in order of digest.
+//
+// All iterates over a consistent view of the store, taken when the iteration starts, and is safe to use concurrently with Store.
+//
+// Example
+//
+//	var mem *memdigest.SHA256
+//	
+//	// ...
+//	
+//	for digest, size := range mem.All() {
+//		fmt.Printf("%x %d\n", digest, size)
+//	}
+func (receiver *SHA256) All() iter.Seq2[[sha256.Size]byte, int] {
+	return func(yield func([sha256.Size]byte, int) bool) {
+		receiver.Range(yield)
+	}
+}
+
+// Insert stores ‘content’ and returns the SHA-256 digest of ‘content’, and whether ‘content’ was newly inserted.
+//
+// If ‘content’ was already stored, then Insert returns without copying ‘content’ again, and ‘inserted’ is false.
+func (receiver *SHA256) Insert(content []byte) (digest [sha256.Size]byte, inserted bool, err error) {
+	if nil == receiver {
+		return [sha256.Size]byte{}, false, errNilReceiver
+	}
+
+	key, inserted, err := receiver.storage().Insert(content)
+	if nil != err {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	copy(digest[:], key)
+
+	return digest, inserted, nil
+}
+
+// NamedInformation returns the RFC 6920 named information URI for the stored content whose SHA-256 digest is ‘digest’,
+// using the suite named ‘suite’ (either "sha-256", or one of the truncated suites, ex: "sha-256-128").
+//
+// Example
+//
+//	// location == "ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro"
+//	location, err := mem.NamedInformation(digest[:], "sha-256")
+func (receiver *SHA256) NamedInformation(digest []byte, suite string) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	return receiver.storage().NamedInformation(digest, suite)
+}
+
+// OnEvict sets the function that is called (with the SHA-256 digest and length of the content) whenever content is evicted.
+func (receiver *SHA256) OnEvict(fn func(digest [sha256.Size]byte, size int)) {
+	if nil == receiver {
+		return
+	}
+
+	if nil == fn {
+		receiver.storage().OnEvict(nil)
+		return
+	}
+
+	receiver.storage().OnEvict(func(digest string, size int) {
+		var key [sha256.Size]byte
+		copy(key[:], digest)
+
+		fn(key, size)
+	})
+}
+
+// Range calls ‘fn’ with the SHA-256 digest and length of each piece of content in the store, in order of digest, until ‘fn’ returns false.
+//
+// Range iterates over a consistent view of the store, taken when Range is called, and is safe to call concurrently with Store.
+func (receiver *SHA256) Range(fn func(digest [sha256.Size]byte, size int) bool) {
+	if nil == receiver {
+		return
+	}
+
+	receiver.storage().Range(func(digest string, size int) bool {
+		var key [sha256.Size]byte
+		copy(key[:], digest)
+
+		return fn(key, size)
+	})
+}
+
+// Resolve returns the digest of the stored content whose hexadecimal digest begins with ‘prefix’, if there is exactly one.
+//
+// If more than one digest begins with ‘prefix’, then Resolve returns a memdigest.AmbiguousPrefix error, which lists them.
+// If none do, then Resolve returns a memdigest.PrefixNotFound error.
+//
+// Example
+//
+//	digest, err := mem.Resolve("c0535e4b")
+func (receiver *SHA256) Resolve(prefix string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+
+	if nil == receiver {
+		return digest, errNilReceiver
+	}
+
+	resolved, err := receiver.storage().Resolve(prefix)
+	if nil != err {
+		return digest, err
+	}
+
+	copy(digest[:], resolved)
+
+	return digest, nil
+}
+
+// Store stores ‘content’ and returns the SHA-256 digest of ‘content’.
+//
+// Example
+//
+// Here is an example of it being used:
+//
+//	var mem *memdigest.SHA256
+//	
+//	// ...
+//	
+//	var content []byte = []byte("The request has been fulfilled and resulted in a new resource being created.")
+//	
+//	// ...
+//	
+//	digest, err := mem.Store(content)
+//
+// The returned digest is in binary form, not hexadecimal.
+//
+// In the case of our example, it will be:
+//
+//	[32]byte{0x39, 0x23, 0x3d, 0xeb, 0x92, 0x16, 0xd0, 0x56, 0x61, 0xac, 0x45, 0x09, 0x01, 0x0e, 0x5f, 0x4a, 0xa8, 0x38, 0x87, 0x4e, 0x97, 0xe1, 0x6e, 0x00, 0x93, 0xda, 0x2a, 0xc3, 0xdf, 0xf6, 0xfa, 0x66}
+//
+// If you want to convert it to hexadecimal, you can do so with code such as:
+//
+//	hexadecimalDigest := fmt.Sprintf("%x", digest)
+//
+// Which will return the string:
+//
+//	"39233deb9216d05661ac4509010e5f4aa838874e97e16e0093da2ac3dff6fa66"
+func (receiver *SHA256) Store(content []byte) ([sha256.Size]byte, error) {
+	if nil == receiver {
+		return [sha256.Size]byte{}, errNilReceiver
+	}
+
+	digest, err := receiver.storage().Store(content)
+	if nil != err {
+		return [sha256.Size]byte{}, err
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], digest)
+
+	return key, nil
+}
+
+// StoreFrom stores the content read from ‘r’ (until io.EOF), and returns the SHA-256 digest of that content.
+//
+// Unlike Store, StoreFrom does not need the whole content to be in memory before it is stored.
+// The content is hashed while it is being copied into the store's own buffer.
+func (receiver *SHA256) StoreFrom(r io.Reader) ([sha256.Size]byte, error) {
+	if nil == receiver {
+		return [sha256.Size]byte{}, errNilReceiver
+	}
+
+	digest, err := receiver.storage().StoreFrom(r)
+	if nil != err {
+		return [sha256.Size]byte{}, err
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], digest)
+
+	return key, nil
+}
+
+// StoreFromN stores the next ‘n’ bytes read from ‘r’, and returns the SHA-256 digest of them.
+//
+// If ‘r’ ends before ‘n’ bytes are read, then nothing is stored, and io.ErrUnexpectedEOF is returned.
+func (receiver *SHA256) StoreFromN(r io.Reader, n int64) ([sha256.Size]byte, error) {
+	if nil == receiver {
+		return [sha256.Size]byte{}, errNilReceiver
+	}
+
+	digest, err := receiver.storage().StoreFromN(r, n)
+	if nil != err {
+		return [sha256.Size]byte{}, err
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], digest)
+
+	return key, nil
+}
+
+// StoreWithTTL stores ‘content’ and returns the SHA-256 digest of ‘content’.
+//
+// The content expires ‘ttl’ after it was stored, or after it was last read, whichever is later.
+//
+// Example
+//
+//	var mem *memdigest.SHA256
+//	
+//	// ...
+//	
+//	digest, err := mem.StoreWithTTL(content, 5 * time.Minute)
+func (receiver *SHA256) StoreWithTTL(content []byte, ttl time.Duration) ([sha256.Size]byte, error) {
+	if nil == receiver {
+		return [sha256.Size]byte{}, errNilReceiver
+	}
+
+	digest, err := receiver.storage().StoreWithTTL(content, ttl)
+	if nil != err {
+		return [sha256.Size]byte{}, err
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], digest)
+
+	return key, nil
+}
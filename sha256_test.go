@@ -0,0 +1,146 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"github.com/reiver/go-digestfs"
+	"github.com/reiver/go-digestfs/driver"
+
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"testing"
+)
+
+func TestSHA256AsDigestFSDriverMountPoint(t *testing.T) {
+
+	var mountpoint digestfs_driver.MountPoint = new(memdigest.SHA256) // THIS IS WHAT ACTUALLY MATTERS!
+
+	if nil == mountpoint {
+		t.Error("This should never happen.")
+	}
+}
+
+func TestSHA256(t *testing.T) {
+
+	tests := []struct{
+		Content string
+		Expected string
+		ExpectedLocation string
+	}{
+		{
+			Content: "Hello world!",
+			Expected: "c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a",
+			ExpectedLocation: "memdigest:sha-256:hexadecimal(c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0",
+		},
+		{
+			Content: "apple",
+			Expected: "3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b",
+			ExpectedLocation: "memdigest:sha-256:hexadecimal(3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b)/0",
+		},
+		{
+			Content: "BANANA",
+			Expected: "82379da710fc913d545b2d3ea7c6b7a48e5cc9f3c8c7f63a7927be3153325109",
+			ExpectedLocation: "memdigest:sha-256:hexadecimal(82379da710fc913d545b2d3ea7c6b7a48e5cc9f3c8c7f63a7927be3153325109)/0",
+		},
+	}
+
+	var mem memdigest.SHA256
+
+	for testNumber, test := range tests {
+		actual, err := mem.Store([]byte(test.Content))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect to get an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, fmt.Sprintf("%x", actual[:]); expected != actual {
+			t.Errorf("For test #%d, the SHA-256 that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			t.Logf("CONTENT: %q", test.Content)
+			continue
+		}
+	}
+
+	for testNumber, test := range tests {
+
+		digest, err := hex.DecodeString(test.Expected)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		{
+			value, found := mem.Load(digest)
+			if !found {
+				t.Errorf("For test #%d, expected value to exist for the SHA-256 digest.", testNumber)
+				t.Logf("SHA-256 digest: %s", test.Expected)
+				continue
+			}
+			if expected, actual := test.Content, value; expected != actual {
+				t.Errorf("For test #%d, the actual value is not what was expected.", testNumber)
+				t.Logf("EXPECTED: %q", expected)
+				t.Logf("ACTUAL:   %q", actual)
+				continue
+			}
+		}
+
+		{
+			_, err := mem.Open("SHA-1", string(digest))
+			if nil == err {
+				t.Errorf("For test #%d, expected an error, but did not actually get one: %#v", testNumber, err)
+				continue
+			}
+			switch err.(type) {
+			case digestfs.UnsupportedAlgorithm:
+				// Nothing here.
+			default:
+				t.Errorf("For test #%d, expected error to be UnsupportedAlgorithm, but actually wasn't: (%T) %q", testNumber, err, err)
+				continue
+			}
+		}
+
+		for _, open := range []func() (digestfs_driver.Content, error){
+			func() (digestfs_driver.Content, error) { return mem.Open("SHA-256", string(digest)) },
+			func() (digestfs_driver.Content, error) { return mem.OpenLocation(test.ExpectedLocation) },
+		} {
+			content, err := open()
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				continue
+			}
+
+			r := io.NewSectionReader(content, 0, int64(content.Len()))
+			value, err := ioutil.ReadAll(r)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				continue
+			}
+
+			if expected, actual := test.Content, string(value); expected != actual {
+				t.Errorf("For test #%d, the actual value is not what was expected.", testNumber)
+				t.Logf("EXPECTED: %q", expected)
+				t.Logf("ACTUAL:   %q", actual)
+				continue
+			}
+		}
+	}
+
+	if err := mem.Unmount(); nil != err {
+		t.Errorf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	{
+		nonExistentDigest := sha256.Sum256([]byte("Hello world!"))
+
+		_, found := mem.Load(nonExistentDigest[:])
+		if found {
+			t.Errorf("Did not expect value to exist for the SHA-256 digest after Unmount.")
+			t.Logf("SHA-256 digest: %x", nonExistentDigest)
+		}
+	}
+}
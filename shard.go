@@ -0,0 +1,503 @@
+package memdigest
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shardCount is the number of shards a Store splits its content across.
+//
+// Each shard has its own lock, so that operations on content in different shards do not contend with each other.
+const shardCount = 64
+
+// shard is one part of the content of a Store.
+//
+// Which shard a piece of content goes into is decided by the leading byte of its digest.
+type shard struct {
+	mutex sync.RWMutex
+	data map[string]*entry
+
+	// shared is whether ‘data’ is shared with a Snapshot, and so has to be copied before it is changed (see writable).
+	shared bool
+
+	// index is the keys of ‘data’, in order, for Resolve. It is only built if ‘indexed’ is true;
+	// after that, it is kept up to date as keys are added to, and removed from, ‘data’ (see indexAdd and indexRemove).
+	index []string
+	indexed bool
+
+	// frozen is whether the store is frozen (see Freeze). It is set while the (write) lock is held,
+	// so that a change that was waiting for the lock, while the store was being frozen, is not then made to ‘data’,
+	// which readers of a frozen store read without the lock.
+	frozen bool
+
+	// blobs and bytes are how many pieces of content are in ‘data’, and their total length.
+	// largest is the length of the longest of them, unless ‘largestStale’ is true (because the longest was removed).
+	// They are only changed while the (write) lock is held, but are atomic, so that Stats can read them without the lock.
+	blobs atomic.Int64
+	bytes atomic.Int64
+	largest atomic.Int64
+	largestStale atomic.Bool
+
+	// stats are the counts, of stores and loads of content in the shard, that Stats adds up.
+	stats counters
+
+	// (The padding keeps what one shard changes off the cache line of the next shard, so that goroutines that use different shards do not slow each other down.)
+	_ [64]byte
+}
+
+// entry is a single piece of stored content.
+type entry struct {
+	value string
+
+	// ttl is how long (in nanoseconds) after it was stored, or last read, the content expires.
+	// Zero means the content never expires.
+	ttl atomic.Int64
+
+	// expires is when (in Unix nanoseconds) the content expires, if ‘ttl’ is not zero.
+	expires atomic.Int64
+
+	// pins is how many times the content is pinned. Content that is pinned is never removed.
+	// It is guarded by the shard's lock.
+	pins int
+}
+
+func newEntry(value string, ttl time.Duration, now time.Time) *entry {
+	var e entry
+
+	e.value = value
+	e.ttl.Store(int64(ttl))
+	if 0 < ttl {
+		e.expires.Store(now.Add(ttl).UnixNano())
+	}
+
+	return &e
+}
+
+// expired returns whether the content has expired as of ‘now’.
+func (receiver *entry) expired(now time.Time) bool {
+	if 0 == receiver.ttl.Load() {
+		return false
+	}
+
+	return receiver.expires.Load() <= now.UnixNano()
+}
+
+// expiring returns whether the content can expire.
+func (receiver *entry) expiring() bool {
+	return 0 != receiver.ttl.Load()
+}
+
+// used records that the content was used at ‘now’, which pushes back when it expires.
+//
+// It never brings forward when the content expires; so that a read that loaded a shorter ‘ttl’, racing with a call to extend, cannot undo it.
+func (receiver *entry) used(now time.Time) {
+	ttl := receiver.ttl.Load()
+	if 0 == ttl {
+		return
+	}
+
+	expires := now.UnixNano() + ttl
+	for {
+		current := receiver.expires.Load()
+		if expires <= current {
+			return
+		}
+		if receiver.expires.CompareAndSwap(current, expires) {
+			return
+		}
+	}
+}
+
+// extend makes the content live at least as long as if it had been stored at ‘now’ with ‘ttl’.
+//
+// Content that never expires continues to never expire. And a ‘ttl’ of zero makes the content never expire.
+//
+// extend can be called without the shard's lock (see Store.store), and so changes ‘ttl’ with compare-and-swap,
+// so that it never overwrites a ‘ttl’ of zero that another call set.
+func (receiver *entry) extend(ttl time.Duration, now time.Time) {
+	for {
+		current := receiver.ttl.Load()
+		if 0 == current {
+			return
+		}
+		if 0 != ttl && int64(ttl) <= current {
+			break
+		}
+		if receiver.ttl.CompareAndSwap(current, int64(ttl)) {
+			break
+		}
+	}
+
+	receiver.used(now)
+}
+
+// writable makes ‘data’ safe to change, by first copying it, if it is shared with a Snapshot.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) writable() {
+	if !receiver.shared {
+		return
+	}
+
+	data := make(map[string]*entry, len(receiver.data))
+	for key, e := range receiver.data {
+		data[key] = e
+	}
+
+	receiver.data = data
+	receiver.shared = false
+}
+
+// added records that ‘e’ was added to ‘data’.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) added(e *entry) {
+	size := int64(len(e.value))
+
+	receiver.blobs.Add(1)
+	receiver.bytes.Add(size)
+	if receiver.largest.Load() < size {
+		receiver.largest.Store(size)
+	}
+}
+
+// removed records that ‘e’ was removed from ‘data’.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) removed(e *entry) {
+	size := int64(len(e.value))
+
+	receiver.blobs.Add(-1)
+	receiver.bytes.Add(-size)
+	if receiver.largest.Load() <= size {
+		receiver.largestStale.Store(true)
+	}
+}
+
+// largestBlob returns the length of the longest piece of content in the shard.
+//
+// If the longest piece of content was removed since this was last called, then this finds the new longest (taking the read lock to do so).
+func (receiver *shard) largestBlob() int64 {
+	if !receiver.largestStale.Load() {
+		return receiver.largest.Load()
+	}
+
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	// (While the read lock is held, nothing else changes ‘largest’; except another call to this, which finds the same length.)
+	if !receiver.largestStale.Load() {
+		return receiver.largest.Load()
+	}
+
+	var largest int64
+	for _, e := range receiver.data {
+		if size := int64(len(e.value)); largest < size {
+			largest = size
+		}
+	}
+
+	receiver.largest.Store(largest)
+	receiver.largestStale.Store(false)
+
+	return largest
+}
+
+// reset records that ‘data’ was emptied.
+//
+// The caller must hold the (write) lock.
+func (receiver *shard) reset() {
+	receiver.blobs.Store(0)
+	receiver.bytes.Store(0)
+	receiver.largest.Store(0)
+	receiver.largestStale.Store(false)
+}
+
+// shardIndex returns the index of the shard that content whose digest begins with the byte ‘b’ goes into.
+func shardIndex(b byte) int {
+	return int(b) % shardCount
+}
+
+func (receiver *shard) get(key string) *entry {
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	return receiver.data[key]
+}
+
+func (receiver *shard) getBytes(key []byte) *entry {
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	return receiver.data[string(key)]
+}
+
+// insert stores ‘value’ under ‘key’, and returns whether it was newly inserted.
+//
+// If ‘value’ was already stored (and has not expired), then its expiry is extended by ‘ttl’ (see entry.extend).
+//
+// If ‘lru’ is enabled, it starts keeping track of ‘key’.
+//
+// If the store is frozen, then nothing is stored, and ErrReadOnly is returned.
+func (receiver *shard) insert(key string, value string, ttl time.Duration, lru *lru) (bool, error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return false, ErrReadOnly
+	}
+
+	if nil == receiver.data {
+		receiver.data = map[string]*entry{}
+	}
+
+	previous, found := receiver.data[key]
+
+	// (Only content that can expire needs the time, which is not free to get.)
+	var now time.Time
+	if 0 != ttl || (found && previous.expiring()) {
+		now = time.Now()
+	}
+
+	if found && (!previous.expired(now) || 0 < previous.pins) {
+		previous.extend(ttl, now)
+		return false, nil
+	}
+
+	receiver.writable()
+	if found {
+		receiver.removed(previous)
+	}
+	e := newEntry(value, ttl, now)
+	receiver.data[key] = e
+	receiver.added(e)
+	receiver.indexAdd(key)
+
+	if nil != lru && lru.isEnabled() {
+		lru.add(key, len(value))
+	}
+
+	return true, nil
+}
+
+// delete removes whatever is stored under ‘key’, and returns whether anything was removed.
+//
+// Content that is pinned is not removed; instead, how many times it is pinned is returned.
+//
+// If ‘lru’ is not nil, it stops keeping track of ‘key’.
+//
+// If the store is frozen, then nothing is removed, and ErrReadOnly is returned.
+func (receiver *shard) delete(key string, lru *lru) (removed bool, pins int, err error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return false, 0, ErrReadOnly
+	}
+
+	e, found := receiver.data[key]
+	if !found {
+		return false, 0, nil
+	}
+	if 0 < e.pins {
+		return false, e.pins, nil
+	}
+
+	receiver.writable()
+	delete(receiver.data, key)
+	receiver.removed(e)
+	receiver.indexRemove(key)
+
+	if nil != lru {
+		lru.remove(key)
+	}
+
+	return true, 0, nil
+}
+
+// evict removes whatever is stored under ‘key’, which ‘lru’ has already stopped keeping track of, and returns whether anything was removed.
+//
+// Content that is pinned is not removed; instead, ‘lru’ starts keeping track of it again.
+// If the store is frozen, then nothing is removed.
+func (receiver *shard) evict(key string, lru *lru) bool {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return false
+	}
+
+	e, found := receiver.data[key]
+	if !found {
+		return false
+	}
+	if 0 < e.pins {
+		lru.add(key, len(e.value))
+		lru.pin(key, true)
+		return false
+	}
+
+	receiver.writable()
+	delete(receiver.data, key)
+	receiver.removed(e)
+	receiver.indexRemove(key)
+
+	return true
+}
+
+// pin pins whatever is stored under ‘key’, and returns whether anything was found to pin.
+//
+// If the store is frozen, then nothing is pinned, and ErrReadOnly is returned.
+func (receiver *shard) pin(key string, lru *lru) (bool, error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return false, ErrReadOnly
+	}
+
+	e, found := receiver.data[key]
+	if !found {
+		return false, nil
+	}
+
+	e.pins++
+	lru.pin(key, true)
+
+	return true, nil
+}
+
+// unpin undoes one pin of whatever is stored under ‘key’.
+//
+// If the store is frozen, then nothing is unpinned, and ErrReadOnly is returned.
+func (receiver *shard) unpin(key string, lru *lru) (found bool, pinned bool, err error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return false, false, ErrReadOnly
+	}
+
+	e, found := receiver.data[key]
+	if !found {
+		return false, false, nil
+	}
+	if e.pins < 1 {
+		return true, false, nil
+	}
+
+	e.pins--
+	if 0 == e.pins {
+		lru.pin(key, false)
+	}
+
+	return true, true, nil
+}
+
+func (receiver *shard) pins(key string) int {
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	e, found := receiver.data[key]
+	if !found {
+		return 0
+	}
+
+	return e.pins
+}
+
+// deleteExpired removes whatever is stored under ‘key’, if it has expired as of ‘now’ (and is not pinned),
+// and returns whether nothing is stored under ‘key’ anymore.
+//
+// If the store is frozen, then nothing is removed, but expired content still counts as no longer stored.
+func (receiver *shard) deleteExpired(key string, now time.Time, lru *lru) bool {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	e, found := receiver.data[key]
+	if !found {
+		return true
+	}
+	if !e.expired(now) || 0 < e.pins {
+		return false
+	}
+
+	if receiver.frozen {
+		return true
+	}
+
+	receiver.writable()
+	delete(receiver.data, key)
+	receiver.removed(e)
+	receiver.indexRemove(key)
+
+	if nil != lru {
+		lru.remove(key)
+	}
+
+	return true
+}
+
+// sweep removes everything that has expired as of ‘now’ (and is not pinned). If the store is frozen, then nothing is removed.
+func (receiver *shard) sweep(now time.Time, lru *lru) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return
+	}
+
+	var removed bool
+
+	for key, e := range receiver.data {
+		if !e.expired(now) {
+			continue
+		}
+		if 0 < e.pins {
+			continue
+		}
+
+		// (If this copies the map, then the rest of the range is over the original map, which the Snapshot still has, unchanged.)
+		receiver.writable()
+		delete(receiver.data, key)
+		receiver.removed(e)
+		removed = true
+
+		if nil != lru {
+			lru.remove(key)
+		}
+	}
+
+	// (Rather than removing each key from the index one at a time, the index is filtered once.)
+	if removed {
+		receiver.indexRetain()
+	}
+}
+
+// sweepKey removes whatever is stored under ‘key’ (unless it is pinned), and returns its length and whether it was removed.
+// If the store is frozen, then nothing is removed.
+func (receiver *shard) sweepKey(key string, lru *lru) (int, bool) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if receiver.frozen {
+		return 0, false
+	}
+
+	e, found := receiver.data[key]
+	if !found || 0 < e.pins {
+		return 0, false
+	}
+
+	receiver.writable()
+	delete(receiver.data, key)
+	receiver.removed(e)
+	receiver.indexRemove(key)
+
+	if nil != lru {
+		lru.remove(key)
+	}
+
+	return len(e.value), true
+}
@@ -0,0 +1,117 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+
+	"testing"
+)
+
+// unshardedSHA1 is how memdigest.SHA1 stored its content just before it was split into shards:
+// a single map guarded by a single sync.RWMutex, with the hashing (and copying) done outside the lock,
+// and content that is already stored found with just the read lock.
+//
+// It is only here so that the benchmarks can compare against it.
+type unshardedSHA1 struct {
+	mutex sync.RWMutex
+	data map[string]string
+}
+
+func (receiver *unshardedSHA1) Load(digest []byte) (string, bool) {
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	value, found := receiver.data[string(digest)]
+
+	return value, found
+}
+
+func (receiver *unshardedSHA1) Store(content []byte) ([sha1.Size]byte, error) {
+	digest := sha1.Sum(content)
+	key := string(digest[:])
+
+	if receiver.contains(key) {
+		return digest, nil
+	}
+
+	value := string(content)
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if nil == receiver.data {
+		receiver.data = map[string]string{}
+	}
+
+	if _, found := receiver.data[key]; !found {
+		receiver.data[key] = value
+	}
+
+	return digest, nil
+}
+
+func (receiver *unshardedSHA1) contains(key string) bool {
+	receiver.mutex.RLock()
+	defer receiver.mutex.RUnlock()
+
+	_, found := receiver.data[key]
+
+	return found
+}
+
+type loadStorer interface {
+	Load([]byte) (string, bool)
+	Store([]byte) ([sha1.Size]byte, error)
+}
+
+// benchmarkMixed runs a mixed workload, of 1 Store (of content that is not stored yet) for every 8 Loads, from many goroutines at once.
+func benchmarkMixed(b *testing.B, mem loadStorer) {
+
+	const preloaded = 1024
+
+	var digests [][sha1.Size]byte
+	for i := 0; i < preloaded; i++ {
+		var content [16]byte
+		binary.BigEndian.PutUint64(content[8:], uint64(i))
+
+		digest, err := mem.Store(content[:])
+		if nil != err {
+			b.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+		digests = append(digests, digest)
+	}
+
+	// Each goroutine stores content that begins with its own number, so that what each stores is new.
+	var goroutines atomic.Uint64
+
+	b.SetParallelism(8)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var content [16]byte
+		binary.BigEndian.PutUint64(content[:8], goroutines.Add(1))
+
+		for i := 0; pb.Next(); i++ {
+			if 0 == i % 9 {
+				binary.BigEndian.PutUint64(content[8:], uint64(i))
+				mem.Store(content[:])
+				continue
+			}
+
+			digest := digests[i % preloaded]
+			mem.Load(digest[:])
+		}
+	})
+}
+
+func BenchmarkSHA1Mixed(b *testing.B) {
+	benchmarkMixed(b, new(memdigest.SHA1))
+}
+
+func BenchmarkUnshardedSHA1Mixed(b *testing.B) {
+	benchmarkMixed(b, new(unshardedSHA1))
+}
@@ -0,0 +1,272 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+func init() {
+	var mounter digestfs_driver.Mounter = digestfs_driver.MounterFunc(func(args ...interface{}) (digestfs_driver.MountPoint, error){
+		if expected, actual := 1, len(args); expected != actual {
+			return nil, fmt.Errorf("memdigest: Wrong Number Of Arguments: expected %d, but actually got %d", expected, actual)
+		}
+
+		arg0 := args[0]
+
+		snapshot, casted := arg0.(*Snapshot)
+		if !casted {
+			return nil, fmt.Errorf("memdigest: Wrong Type: expected *memdigest.Snapshot, but actually got %T", arg0)
+		}
+		if nil == snapshot {
+			return nil, errNilReceiver
+		}
+
+		return snapshot, nil
+	})
+
+	digestfs_driver.Registry.Register(mounter, "memdigest.Snapshot")
+}
+
+// Snapshot is an immutable, point-in-time view of a Store.
+//
+// A Snapshot is taken with the Snapshot method of a store. Taking a snapshot is cheap: it does not copy the content,
+// nor the store's index of the content. Instead, the store copies (one shard of) its index the first time it changes it after the snapshot was taken.
+// (The snapshot does record which content had expired when it was taken, which means looking at each piece of content, but not copying it.)
+//
+// The store keeps accepting Store, Delete, etc, while the snapshot keeps serving what was stored when it was taken.
+//
+// *memdigest.Snapshot fits the digestfs_driver.MountPoint interface, read-only. (Create always returns an error.)
+// It can be mounted under its own name:
+//
+// Example
+//
+//	snapshot := mem.Snapshot()
+//
+//	var mountpoint digestfs.MountPoint
+//
+//	err := mountpoint.Mount("memdigest.Snapshot", snapshot)
+type Snapshot struct {
+	algorithm string
+	size int
+	data atomic.Pointer[snapshotData] // nil once unmounted
+}
+
+// snapshotData is what a Snapshot serves.
+//
+// ‘data’ is shared with the store, which keeps changing when the entries in it expire (as their content is read, stored again, pinned, etc).
+// So, rather than working out from an entry whether its content has expired, the snapshot looks in ‘expired’,
+// which has the keys of the content that had expired (and was not pinned) when the snapshot was taken.
+type snapshotData struct {
+	data [shardCount]map[string]*entry
+	expired [shardCount]map[string]struct{}
+}
+
+// lookup returns the content stored under ‘key’, if it was stored, and had not expired, when the snapshot was taken.
+func (receiver *snapshotData) lookup(key string) (string, bool) {
+	i := shardIndex(key[0])
+
+	e, found := receiver.data[i][key]
+	if !found {
+		return "", false
+	}
+	if _, expired := receiver.expired[i][key]; expired {
+		return "", false
+	}
+
+	return e.value, true
+}
+
+// Snapshot returns an immutable, point-in-time view of the store.
+func (receiver *Store) Snapshot() *Snapshot {
+	if nil == receiver {
+		return nil
+	}
+
+	var snapshot Snapshot
+
+	snapshot.algorithm = receiver.algorithm
+	snapshot.size = receiver.size
+
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.Lock()
+	}
+
+	now := time.Now()
+
+	var data snapshotData
+	for i := range receiver.shards {
+		shard := &receiver.shards[i]
+
+		data.data[i] = shard.data
+		shard.shared = true
+
+		// Content that has expired, but is pinned, can still be used, as it can in the store.
+		for key, e := range shard.data {
+			if !e.expired(now) || 0 < e.pins {
+				continue
+			}
+
+			if nil == data.expired[i] {
+				data.expired[i] = map[string]struct{}{}
+			}
+			data.expired[i][key] = struct{}{}
+		}
+	}
+	snapshot.data.Store(&data)
+
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.Unlock()
+	}
+
+	return &snapshot
+}
+
+// Algorithm returns the name of the hash algorithm the snapshot's store uses.
+func (receiver *Snapshot) Algorithm() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.algorithm
+}
+
+// Create makes *memdigest.Snapshot fit the digestfs_driver.MountPoint interface.
+//
+// A snapshot is read-only, so Create always returns ErrReadOnly.
+func (receiver *Snapshot) Create(p []byte) (algorithm string, digest string, err error) {
+	if nil == receiver {
+		return "", "", errNilReceiver
+	}
+
+	return receiver.algorithm, "", ErrReadOnly
+}
+
+// Load returns the content whose digest is ‘digest’, if it was stored when the snapshot was taken.
+func (receiver *Snapshot) Load(digest []byte) (string, bool) {
+	if nil == receiver {
+		return "", false
+	}
+
+	return receiver.load(string(digest))
+}
+
+func (receiver *Snapshot) load(digest string) (string, bool) {
+	if receiver.size != len(digest) || "" == digest {
+		return "", false
+	}
+
+	data := receiver.data.Load()
+	if nil == data {
+		return "", false
+	}
+
+	return data.lookup(digest)
+}
+
+// Open makes *memdigest.Snapshot fit the digestfs_driver.MountPoint interface.
+func (receiver *Snapshot) Open(algorithm string, digest string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	if receiver.algorithm != algorithm {
+		return nil, digestfs_driver.ErrUnsupportedAlgorithm(algorithm)
+	}
+
+	value, found := receiver.load(digest)
+	if !found {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	return newContent(value), nil
+}
+
+// OpenLocation makes *memdigest.Snapshot fit the digestfs_driver.MountPoint interface.
+//
+// Locations are the same as those of the snapshot's store, except that the digest cannot be abbreviated (or truncated).
+func (receiver *Snapshot) OpenLocation(location string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	parsed, ok := parseLocation(location)
+	if !ok {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+	digest, err := hex.DecodeString(parsed.digestHexadecimal)
+	if nil != err {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+	if strings.ToLower(receiver.algorithm) != parsed.algorithm {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	value, found := receiver.load(string(digest))
+	if !found {
+		return nil, digestfs_driver.ErrContentNotFound(receiver.algorithm, string(digest))
+	}
+
+	return locationContent(location, value, parsed)
+}
+
+// Range calls ‘fn’ with the digest and length of each piece of content in the snapshot, in order of digest, until ‘fn’ returns false.
+//
+// The digest is in binary form, not hexadecimal.
+func (receiver *Snapshot) Range(fn func(digest string, size int) bool) {
+	if nil == receiver {
+		return
+	}
+
+	data := receiver.data.Load()
+	if nil == data {
+		return
+	}
+
+	var items []item
+	for i := range data.data {
+		for key, e := range data.data[i] {
+			if _, expired := data.expired[i][key]; expired {
+				continue
+			}
+
+			items = append(items, item{key:key, value:e.value})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key < items[j].key
+	})
+
+	for _, item := range items {
+		if !fn(item.key, len(item.value)) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the digest and length of each piece of content in the snapshot, in order of digest.
+func (receiver *Snapshot) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		receiver.Range(yield)
+	}
+}
+
+// Unmount makes *memdigest.Snapshot fit the digestfs_driver.MountPoint interface.
+//
+// Unmount lets go of the snapshot's view of the store. After that, the snapshot is empty.
+func (receiver *Snapshot) Unmount() error {
+	if nil == receiver {
+		return nil
+	}
+
+	receiver.data.Store(nil)
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sync"
+
+	"testing"
+	"time"
+)
+
+func TestSHA1Snapshot(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	digestApple, _ := mem.Store([]byte("apple"))
+	digestBanana, _ := mem.Store([]byte("BANANA"))
+
+	snapshot := mem.Snapshot()
+
+	mem.Delete(digestApple[:])
+	digestCherry, _ := mem.Store([]byte("cherry"))
+
+	tests := []struct{
+		Digest [sha1.Size]byte
+		ExpectedInSnapshot bool
+		ExpectedInStore bool
+	}{
+		{
+			Digest: digestApple,
+			ExpectedInSnapshot: true,
+			ExpectedInStore: false,
+		},
+		{
+			Digest: digestBanana,
+			ExpectedInSnapshot: true,
+			ExpectedInStore: true,
+		},
+		{
+			Digest: digestCherry,
+			ExpectedInSnapshot: false,
+			ExpectedInStore: true,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		_, inSnapshot := snapshot.Load(test.Digest[:])
+		if expected, actual := test.ExpectedInSnapshot, inSnapshot; expected != actual {
+			t.Errorf("For test #%d, whether the content was actually in the snapshot was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %t", expected)
+			t.Logf("ACTUAL:   %t", actual)
+			continue
+		}
+
+		_, inStore := mem.Load(test.Digest[:])
+		if expected, actual := test.ExpectedInStore, inStore; expected != actual {
+			t.Errorf("For test #%d, whether the content was actually in the store was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %t", expected)
+			t.Logf("ACTUAL:   %t", actual)
+			continue
+		}
+	}
+
+	{
+		content, err := snapshot.OpenLocation(fmt.Sprintf("memdigest:sha-1:hexadecimal(%x)/0", digestApple))
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+
+		p, _ := io.ReadAll(io.NewSectionReader(content, 0, 1<<20))
+		if expected, actual := "apple", string(p); expected != actual {
+			t.Errorf("The content that was actually opened from the snapshot was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+
+	if _, _, err := snapshot.Create([]byte("durian")); nil == err {
+		t.Errorf("Expected an error from creating content in a snapshot, but did not actually get one.")
+	}
+}
+
+func TestSHA1SnapshotConcurrent(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	var digests [][sha1.Size]byte
+	for i := 0; i < 256; i++ {
+		digest, _ := mem.Store([]byte(fmt.Sprintf("content #%d", i)))
+		digests = append(digests, digest)
+	}
+
+	snapshot := mem.Snapshot()
+
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		for _, digest := range digests {
+			mem.Delete(digest[:])
+			mem.Store([]byte(fmt.Sprintf("new content %x", digest)))
+		}
+	}()
+
+	for _, digest := range digests {
+		if _, found := snapshot.Load(digest[:]); !found {
+			t.Errorf("Expected the content to still be in the snapshot, but it actually wasn't: %x", digest)
+		}
+	}
+
+	waitGroup.Wait()
+
+	var count int
+	for range snapshot.All() {
+		count++
+	}
+
+	if expected, actual := 256, count; expected != actual {
+		t.Errorf("The number of pieces of content actually in the snapshot was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
+
+func TestSHA1SnapshotExpiry(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	const ttl = 50 * time.Millisecond
+
+	digestApple, _ := mem.StoreWithTTL([]byte("apple"), ttl)
+	digestBanana, _ := mem.StoreWithTTL([]byte("BANANA"), ttl)
+	digestCherry, _ := mem.StoreWithTTL([]byte("Cherry"), time.Hour)
+
+	if err := mem.Pin(digestApple[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	time.Sleep(2 * ttl)
+
+	snapshot := mem.Snapshot()
+
+	// Pinning, and then storing again, the content that had expired (but not been removed yet) when the snapshot was taken
+	// brings it back in the store, but must not in the snapshot.
+	if err := mem.Pin(digestBanana[:]); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	if _, err := mem.Store([]byte("BANANA")); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	tests := []struct{
+		Digest [sha1.Size]byte
+		ExpectedInSnapshot bool
+		ExpectedInStore bool
+	}{
+		{
+			Digest: digestApple, // expired, but pinned
+			ExpectedInSnapshot: true,
+			ExpectedInStore: true,
+		},
+		{
+			Digest: digestBanana, // expired, and brought back after the snapshot was taken
+			ExpectedInSnapshot: false,
+			ExpectedInStore: true,
+		},
+		{
+			Digest: digestCherry,
+			ExpectedInSnapshot: true,
+			ExpectedInStore: true,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		_, inSnapshot := snapshot.Load(test.Digest[:])
+		if expected, actual := test.ExpectedInSnapshot, inSnapshot; expected != actual {
+			t.Errorf("For test #%d, whether the content was actually in the snapshot was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %t", expected)
+			t.Logf("ACTUAL:   %t", actual)
+			continue
+		}
+
+		_, inStore := mem.Load(test.Digest[:])
+		if expected, actual := test.ExpectedInStore, inStore; expected != actual {
+			t.Errorf("For test #%d, whether the content was actually in the store was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %t", expected)
+			t.Logf("ACTUAL:   %t", actual)
+			continue
+		}
+	}
+
+	var ranged int
+	snapshot.Range(func(string, int) bool {
+		ranged++
+		return true
+	})
+	if expected, actual := 2, ranged; expected != actual {
+		t.Errorf("The number of pieces of content that Range actually went over was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+}
@@ -0,0 +1,79 @@
+package memdigest
+
+import (
+	"sync/atomic"
+)
+
+// Stats are statistics about a store.
+//
+// Blobs, Bytes, and LargestBlob describe what the store holds at the time Stats was called.
+// (This includes content that has expired, but has not been removed yet; see Sweep.)
+// The rest are cumulative counts, since the store was created.
+type Stats struct {
+	Blobs int // how many pieces of content are stored
+	Bytes int64 // the total length of the stored content
+	LargestBlob int // the length of the largest piece of stored content
+
+	Stores uint64 // how many times content was stored (including duplicates)
+	DuplicateStores uint64 // how many times content was stored that was already stored
+	LoadHits uint64 // how many times Load or Open found the content
+	LoadMisses uint64 // how many times Load or Open did not find the content
+	OpenLocationFailures uint64 // how many times OpenLocation returned an error
+}
+
+// counters are (most of) the cumulative counts of a Stats.
+//
+// They are updated atomically, so that keeping count does not add to the contention on the shards' locks.
+// And each shard keeps its own, so that goroutines that use different shards do not contend over the counts either.
+type counters struct {
+	stores atomic.Uint64
+	duplicateStores atomic.Uint64
+	loadHits atomic.Uint64
+	loadMisses atomic.Uint64
+}
+
+func (receiver *counters) stored(inserted bool) {
+	receiver.stores.Add(1)
+	if !inserted {
+		receiver.duplicateStores.Add(1)
+	}
+}
+
+func (receiver *counters) loaded(found bool) {
+	if found {
+		receiver.loadHits.Add(1)
+	} else {
+		receiver.loadMisses.Add(1)
+	}
+}
+
+// Stats returns statistics about the store.
+//
+// Stats does not scan the content; each shard keeps count of what it holds, which are added up instead.
+// (Only if the largest piece of content in a shard was removed does that shard get scanned, to find its new largest.)
+func (receiver *Store) Stats() Stats {
+	if nil == receiver {
+		return Stats{}
+	}
+
+	var stats Stats
+
+	for i := range receiver.shards {
+		shard := &receiver.shards[i]
+
+		stats.Blobs += int(shard.blobs.Load())
+		stats.Bytes += shard.bytes.Load()
+		if largest := int(shard.largestBlob()); stats.LargestBlob < largest {
+			stats.LargestBlob = largest
+		}
+
+		stats.Stores += shard.stats.stores.Load()
+		stats.DuplicateStores += shard.stats.duplicateStores.Load()
+		stats.LoadHits += shard.stats.loadHits.Load()
+		stats.LoadMisses += shard.stats.loadMisses.Load()
+	}
+
+	stats.OpenLocationFailures = receiver.openLocationFailures.Load()
+
+	return stats
+}
@@ -0,0 +1,114 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"testing"
+)
+
+func TestSHA1Stats(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	apple, _ := mem.Store([]byte("apple"))
+	mem.Store([]byte("BANANA"))
+	mem.Store([]byte("apple"))
+
+	mem.Load(apple[:])
+	mem.Open("SHA-1", string(apple[:]))
+	mem.Load(make([]byte, 20))
+
+	mem.OpenLocation("memdigest:sha-1:hexadecimal(d0be2dc421be4fcd0172e5afceea3970e2f3d940)/0")
+	mem.OpenLocation("memdigest:sha-1:hexadecimal(not-hexadecimal)/0")
+	mem.OpenLocation("memdigest:sha-1:hexadecimal(0000000000000000000000000000000000000000)/0")
+
+	expected := memdigest.Stats{
+		Blobs: 2,
+		Bytes: int64(len("apple") + len("BANANA")),
+		LargestBlob: len("BANANA"),
+
+		Stores: 3,
+		DuplicateStores: 1,
+		LoadHits: 3,
+		LoadMisses: 2,
+		OpenLocationFailures: 2,
+	}
+
+	if actual := mem.Stats(); expected != actual {
+		t.Errorf("The stats that were actually gotten were not what was expected.")
+		t.Logf("EXPECTED: %#v", expected)
+		t.Logf("ACTUAL:   %#v", actual)
+	}
+}
+
+func TestSHA1StatsAfterDelete(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	var digests [][20]byte
+	for _, content := range []string{"apple", "BANANA", "Cherry", "dATE", "elderberry"} {
+		digest, _ := mem.Store([]byte(content))
+		digests = append(digests, digest)
+	}
+
+	tests := []struct{
+		Delete int
+		ExpectedBlobs int
+		ExpectedBytes int64
+		ExpectedLargestBlob int
+	}{
+		{
+			Delete: 4, // "elderberry"
+			ExpectedBlobs: 4,
+			ExpectedBytes: int64(len("apple") + len("BANANA") + len("Cherry") + len("dATE")),
+			ExpectedLargestBlob: len("BANANA"),
+		},
+		{
+			Delete: 1, // "BANANA"
+			ExpectedBlobs: 3,
+			ExpectedBytes: int64(len("apple") + len("Cherry") + len("dATE")),
+			ExpectedLargestBlob: len("Cherry"),
+		},
+		{
+			Delete: 2, // "Cherry"
+			ExpectedBlobs: 2,
+			ExpectedBytes: int64(len("apple") + len("dATE")),
+			ExpectedLargestBlob: len("apple"),
+		},
+		{
+			Delete: 0, // "apple"
+			ExpectedBlobs: 1,
+			ExpectedBytes: int64(len("dATE")),
+			ExpectedLargestBlob: len("dATE"),
+		},
+		{
+			Delete: 3, // "dATE"
+			ExpectedBlobs: 0,
+			ExpectedBytes: 0,
+			ExpectedLargestBlob: 0,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		mem.Delete(digests[test.Delete][:])
+
+		stats := mem.Stats()
+
+		if expected, actual := test.ExpectedBlobs, stats.Blobs; expected != actual {
+			t.Errorf("For test #%d, the number of blobs was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+		if expected, actual := test.ExpectedBytes, stats.Bytes; expected != actual {
+			t.Errorf("For test #%d, the number of bytes was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+		if expected, actual := test.ExpectedLargestBlob, stats.LargestBlob; expected != actual {
+			t.Errorf("For test #%d, the largest blob was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %d", expected)
+			t.Logf("ACTUAL:   %d", actual)
+		}
+	}
+}
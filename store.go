@@ -0,0 +1,541 @@
+package memdigest
+
+import (
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func init() {
+	registerStore("memdigest.Store", "")
+}
+
+// registerStore registers a digestfs_driver.Mounter, under the name ‘name’, that accepts a *memdigest.Store
+// (optionally followed by "read-only", to freeze the store, see Freeze, and "multihash", see SetCreateMultihash).
+//
+// If ‘algorithm’ is not empty, then the *memdigest.Store must also be for that algorithm.
+func registerStore(name string, algorithm string) {
+	var mounter digestfs_driver.Mounter = digestfs_driver.MounterFunc(func(args ...interface{}) (digestfs_driver.MountPoint, error){
+		if actual := len(args); actual < 1 {
+			return nil, fmt.Errorf("memdigest: Wrong Number Of Arguments: expected at least 1, but actually got %d", actual)
+		}
+
+		arg0 := args[0]
+
+		options, err := parseMountOptions(args[1:])
+		if nil != err {
+			return nil, err
+		}
+
+		store, casted := arg0.(*Store)
+		if !casted {
+			return nil, fmt.Errorf("memdigest: Wrong Type: expected *memdigest.Store, but actually got %T", arg0)
+		}
+		if nil == store {
+			return nil, errNilReceiver
+		}
+
+		if "" != algorithm && algorithm != store.algorithm {
+			return nil, fmt.Errorf("memdigest: Wrong Algorithm: expected %q, but actually got %q", algorithm, store.algorithm)
+		}
+
+		options.apply(store)
+		store.mounted(name)
+
+		return store, nil
+	})
+
+	digestfs_driver.Registry.Register(mounter, name)
+}
+
+// Store is an in memory content-addressable storage (CAS) for a single hash algorithm.
+//
+// A Store is created with NewStore (or one of NewSHA224, NewSHA384, NewSHA512, and NewSHA512_256).
+// The zero value of Store is not usable.
+//
+// *memdigest.Store fits the digestfs_driver.MountPoint interface.
+type Store struct {
+	algorithm string
+	size int
+	newHash func() hash.Hash
+	hashers sync.Pool // of *hasher (see digest)
+
+	shards [shardCount]shard
+	lru lru
+	janitor janitor
+	journal journal
+	openLocationFailures atomic.Uint64
+	frozen atomic.Bool
+	createMultihash atomic.Bool
+
+	nameMutex sync.Mutex
+	name string
+}
+
+// NewStore returns a new *memdigest.Store for the hash algorithm named ‘algorithm’, whose digests are ‘size’ bytes long,
+// and which are calculated using the hash.Hash returned by ‘newHash’.
+//
+// Example
+//
+//	store := memdigest.NewStore("SHA-256", sha256.Size, sha256.New)
+func NewStore(algorithm string, size int, newHash func() hash.Hash) *Store {
+	var store Store
+
+	store.init(algorithm, size, newHash)
+
+	return &store
+}
+
+func (receiver *Store) init(algorithm string, size int, newHash func() hash.Hash) {
+	receiver.algorithm = algorithm
+	receiver.size = size
+	receiver.newHash = newHash
+}
+
+// Algorithm returns the name of the hash algorithm the store uses.
+//
+// For example: "SHA-256".
+func (receiver *Store) Algorithm() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.algorithm
+}
+
+// Create makes *memdigest.Store fit the digestfs_driver.MountPoint interface.
+//
+// Create is very similar to Store, in that it stores ‘content’ and returns the digest of ‘content’.
+//
+// The returned digest is in binary form, not hexadecimal.
+// (If SetCreateMultihash was used, then Create returns the multihash of the digest instead; see Multihash.)
+func (receiver *Store) Create(p []byte) (algorithm string, digest string, err error) {
+	if nil == receiver {
+		return "", "", errNilReceiver
+	}
+
+	digest, err = receiver.Store(p)
+	if nil != err {
+		return receiver.algorithm, "", err
+	}
+
+	if receiver.createMultihash.Load() {
+		digest, err = receiver.Multihash([]byte(digest))
+		if nil != err {
+			return receiver.algorithm, "", err
+		}
+	}
+
+	return receiver.algorithm, digest, nil
+}
+
+// Load returns the content whose digest is ‘digest’, if it is stored.
+func (receiver *Store) Load(digest []byte) (string, bool) {
+	if nil == receiver {
+		return "", false
+	}
+
+	value, found := receiver.loadBytes(digest)
+	receiver.shard(string(digest)).stats.loaded(found)
+
+	return value, found
+}
+
+func (receiver *Store) loadBytes(digest []byte) (string, bool) {
+	if receiver.size != len(digest) {
+		return "", false
+	}
+
+	if len(digest) < 1 {
+		return "", false
+	}
+
+	e := receiver.entryBytes(digest)
+	if nil == e {
+		return "", false
+	}
+
+	// Only when the content can expire, or when there are limits, does using it need to be recorded.
+	if e.expiring() || receiver.lru.isEnabled() {
+		return receiver.use(string(digest), e)
+	}
+
+	return e.value, true
+}
+
+// Open makes *memdigest.Store fit the digestfs_driver.MountPoint interface.
+//
+// ‘digest’ can be either a digest, or the multihash of a digest (see Multihash).
+//
+// The content returned is a *memdigest.Content, which reads straight from the stored content (without copying it).
+func (receiver *Store) Open(algorithm string, digest string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	if receiver.algorithm != algorithm {
+		return nil, digestfs_driver.ErrUnsupportedAlgorithm(algorithm)
+	}
+
+	if receiver.size != len(digest) {
+		if fromMultihash, ok := receiver.fromMultihash(digest); ok {
+			digest = fromMultihash
+		}
+	}
+
+	value, found := receiver.load(digest)
+	receiver.shard(digest).stats.loaded(found)
+	if !found {
+		return nil, digestfs_driver.ErrContentNotFound(algorithm, digest)
+	}
+
+	return newContent(value), nil
+}
+
+func (receiver *Store) load(digest string) (string, bool) {
+	if receiver.size != len(digest) {
+		return "", false
+	}
+
+	e := receiver.entry(digest)
+	if nil == e {
+		return "", false
+	}
+
+	return receiver.use(digest, e)
+}
+
+// use returns the content of ‘e’, which is stored under ‘key’, and records that it was used.
+//
+// If the content has expired (and is not pinned), then it is removed instead (unless the store is frozen).
+func (receiver *Store) use(key string, e *entry) (string, bool) {
+	now := time.Now()
+
+	if receiver.frozen.Load() {
+		if e.expired(now) && e.pins < 1 {
+			return "", false
+		}
+
+		// (Nothing is evicted from a frozen store, so there is no need to keep track of what was least recently used; which would take the lru's lock.)
+		e.used(now)
+
+		return e.value, true
+	}
+
+	// Content that has expired, but is pinned, is not removed, and so can still be used.
+	if e.expired(now) && receiver.shard(key).deleteExpired(key, now, &receiver.lru) {
+		return "", false
+	}
+
+	e.used(now)
+	receiver.lru.touch(key)
+
+	return e.value, true
+}
+
+// shard returns the shard that content whose digest is ‘digest’ goes into.
+func (receiver *Store) shard(digest string) *shard {
+	if "" == digest {
+		return &receiver.shards[0]
+	}
+
+	return &receiver.shards[shardIndex(digest[0])]
+}
+
+// OpenLocation makes *memdigest.Store fit the digestfs_driver.MountPoint interface.
+//
+// Locations are of the form:
+//
+//	"memdigest:sha-256:hexadecimal(c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0"
+//
+// Where "sha-256" is the (lowercase) name of the store's algorithm, and the trailing "0" is a byte offset into the content
+// (so that, for example, "/6" is of all but the first 6 bytes of the content). Location returns locations of this form.
+//
+// The offset can be followed by a "-" and the offset the location ends at (exclusive), to open a range of the content. For example:
+//
+//	"memdigest:sha-256:hexadecimal(c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/6-11"
+//
+// The range has to fit within the content. The content is not copied.
+//
+// The hexadecimal digest can also be abbreviated, as with abbreviated git object names. For example:
+//
+//	"memdigest:sha-256:hexadecimal(c0535e4b)/0"
+//
+// In which case it is resolved (see Resolve). If it is ambiguous, a memdigest.AmbiguousPrefix error is returned;
+// if no digest begins with it, the same error as for an unabbreviated digest that is not stored is returned
+// (except that the digest in it is the abbreviated hexadecimal digest).
+//
+// Multibase-encoded multihashes (see Multihash) can also be used, instead of the algorithm and hexadecimal digest. For example:
+//
+//	"memdigest:multihash(f1220c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a)/0"
+//
+// (The supported multibase encodings are: base16, base32, base58btc, base64, and base64url.)
+//
+// RFC 6920 named information URIs (see NamedInformation) are also locations. For example:
+//
+//	"ni:///sha-256;wFNeS-K3n_2TKRMFQ2v4iTFOSj-uwF7P_Lt98xrZ5Ro"
+//
+// (Named information URIs that use a truncated suite, ex: "sha-256-32", are resolved the same way as abbreviated hexadecimal digests.)
+func (receiver *Store) OpenLocation(location string) (digestfs_driver.Content, error) {
+	if nil == receiver {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	content, err := receiver.openLocation(location)
+	if nil != err {
+		receiver.openLocationFailures.Add(1)
+	}
+
+	return content, err
+}
+
+func (receiver *Store) openLocation(location string) (digestfs_driver.Content, error) {
+	parsed, ok := parseLocation(location)
+	if !ok {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+	if strings.ToLower(receiver.algorithm) != parsed.algorithm {
+		return nil, digestfs_driver.ErrBadLocation(location)
+	}
+
+	var digest string
+	if 2*receiver.size == len(parsed.digestHexadecimal) {
+		p, err := hex.DecodeString(parsed.digestHexadecimal)
+		if nil != err {
+			return nil, digestfs_driver.ErrBadLocation(location)
+		}
+
+		digest = string(p)
+	} else {
+		resolved, err := receiver.Resolve(parsed.digestHexadecimal)
+		switch err.(type) {
+		case nil:
+			// Nothing here.
+		case AmbiguousPrefix:
+			return nil, err
+		case PrefixNotFound:
+			return nil, digestfs_driver.ErrContentNotFound(receiver.algorithm, parsed.digestHexadecimal)
+		default:
+			return nil, digestfs_driver.ErrBadLocation(location)
+		}
+
+		digest = resolved
+	}
+
+	value, found := receiver.load(digest)
+	receiver.shard(digest).stats.loaded(found)
+	if !found {
+		return nil, digestfs_driver.ErrContentNotFound(receiver.algorithm, digest)
+	}
+
+	return locationContent(location, value, parsed)
+}
+
+// Delete removes the content whose digest is ‘digest’, and returns whether anything was removed.
+//
+// Content that is pinned (see Pin) is not removed; instead, Delete returns a memdigest.Pinned error.
+//
+// If the store is durable (see OpenLog), then the content is also recorded as deleted in the log.
+//
+// (The digestfs_driver.MountPoint interface does not have an equivalent of Delete.)
+func (receiver *Store) Delete(digest []byte) (bool, error) {
+	if nil == receiver {
+		return false, errNilReceiver
+	}
+
+	if receiver.size != len(digest) {
+		return false, nil
+	}
+
+	if receiver.frozen.Load() {
+		return false, ErrReadOnly
+	}
+
+	key := string(digest)
+
+	logged := receiver.journal.isEnabled()
+	if logged {
+		receiver.journal.mutex.Lock()
+		defer receiver.journal.mutex.Unlock()
+
+		// (The store might have been frozen while this was waiting for the lock.)
+		if receiver.frozen.Load() {
+			return false, ErrReadOnly
+		}
+	}
+
+	removed, pins, err := receiver.shard(key).delete(key, &receiver.lru)
+	if nil != err {
+		return false, err
+	}
+	if 0 < pins {
+		return false, Pinned{Digest:key, Count:pins}
+	}
+
+	if removed && logged {
+		if err := receiver.journal.remove(key); nil != err {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// Insert stores ‘content’ and returns the digest of ‘content’, and whether ‘content’ was newly inserted.
+//
+// Because the store is content-addressable, if ‘content’ was already stored then Insert returns
+// without copying ‘content’ again, and ‘inserted’ is false.
+//
+// The returned digest is in binary form, not hexadecimal.
+func (receiver *Store) Insert(content []byte) (digest string, inserted bool, err error) {
+	if nil == receiver {
+		return "", false, errNilReceiver
+	}
+
+	return receiver.store(content, 0)
+}
+
+// store stores ‘content’, which expires ‘ttl’ after it was stored or last read (or never, if ‘ttl’ is zero).
+func (receiver *Store) store(content []byte, ttl time.Duration) (digest string, inserted bool, err error) {
+	if receiver.frozen.Load() {
+		return "", false, ErrReadOnly
+	}
+
+	// The hashing, and the copying of the content, happen before the lock is taken,
+	// so that storing large content does not block other readers and writers.
+	key := receiver.digest(content)
+
+	// (Content that expires, but is now being stored so that it never expires, takes the slow path, so that it is appended to the log, if there is one.)
+	if e := receiver.shard(key).get(key); nil != e && !(0 == ttl && e.expiring()) {
+		// (Only content that can expire needs the time, which is not free to get.)
+		var now time.Time
+		if e.expiring() {
+			now = time.Now()
+		}
+
+		if !e.expired(now) {
+			e.extend(ttl, now)
+			receiver.lru.touch(key)
+			receiver.shard(key).stats.stored(false)
+			return key, false, nil
+		}
+	}
+
+	value := string(content)
+
+	inserted, err = receiver.insert(key, value, ttl)
+	if nil != err {
+		return "", false, err
+	}
+
+	return key, inserted, nil
+}
+
+// hasher is a hash.Hash of a store's algorithm, along with room for its sum.
+type hasher struct {
+	hash hash.Hash
+	sum []byte
+}
+
+// digest returns the digest of ‘content’.
+//
+// The hash.Hash that calculates it is reused, from one call to the next, so that (for small content especially)
+// the only thing that is allocated is the digest itself.
+func (receiver *Store) digest(content []byte) string {
+	h, _ := receiver.hashers.Get().(*hasher)
+	if nil == h {
+		h = &hasher{hash:receiver.newHash()}
+	}
+
+	h.hash.Reset()
+	h.hash.Write(content)
+	h.sum = h.hash.Sum(h.sum[:0])
+
+	key := string(h.sum)
+
+	receiver.hashers.Put(h)
+
+	return key
+}
+
+// Store stores ‘content’ and returns the digest of ‘content’.
+//
+// The returned digest is in binary form, not hexadecimal.
+func (receiver *Store) Store(content []byte) (string, error) {
+	digest, _, err := receiver.Insert(content)
+
+	return digest, err
+}
+
+// insert stores ‘value’ under the (already calculated) digest ‘key’, and returns whether it was newly inserted.
+//
+// If the store is durable (see OpenLog), then ‘value’ is appended to the log first (unless it expires).
+//
+// If storing ‘value’ goes over the store's limits, then the least recently used content is evicted.
+func (receiver *Store) insert(key string, value string, ttl time.Duration) (bool, error) {
+	if receiver.frozen.Load() {
+		return false, ErrReadOnly
+	}
+
+	if 0 == ttl && receiver.journal.isEnabled() {
+		receiver.journal.mutex.Lock()
+		defer receiver.journal.mutex.Unlock()
+
+		// (The store might have been frozen while this was waiting for the lock.)
+		if receiver.frozen.Load() {
+			return false, ErrReadOnly
+		}
+
+		if err := receiver.journal.put(key, value, receiver.shard(key).get(key)); nil != err {
+			return false, err
+		}
+	}
+
+	inserted, err := receiver.shard(key).insert(key, value, ttl, &receiver.lru)
+	if nil != err {
+		return false, err
+	}
+	receiver.shard(key).stats.stored(inserted)
+	if inserted {
+		receiver.evict()
+	}
+
+	return inserted, nil
+}
+
+// Unmount makes *memdigest.Store fit the digestfs_driver.MountPoint interface.
+//
+// Unmount will never return an error, but will (conceptually) remove all content it was previously storing.
+// It also stops the janitor, if one was started with StartJanitor, stops publishing the store's statistics,
+// and closes the log, if one was opened with OpenLog (but does not remove the log).
+func (receiver *Store) Unmount() error {
+	if nil == receiver {
+		return nil
+	}
+
+	receiver.janitor.stop()
+	receiver.journal.close()
+	receiver.SetName("")
+
+	for i := range receiver.shards {
+		receiver.shards[i].mutex.Lock()
+		defer receiver.shards[i].mutex.Unlock()
+	}
+
+	for i := range receiver.shards {
+		receiver.shards[i].data = nil
+		receiver.shards[i].shared = false
+		receiver.shards[i].index = nil
+		receiver.shards[i].indexed = false
+		receiver.shards[i].reset()
+	}
+	receiver.lru.reset()
+
+	return nil
+}
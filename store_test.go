@@ -0,0 +1,155 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"github.com/reiver/go-digestfs"
+	"github.com/reiver/go-digestfs/driver"
+
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"testing"
+)
+
+func TestStoreAsDigestFSDriverMountPoint(t *testing.T) {
+
+	var mountpoint digestfs_driver.MountPoint = memdigest.NewSHA512() // THIS IS WHAT ACTUALLY MATTERS!
+
+	if nil == mountpoint {
+		t.Error("This should never happen.")
+	}
+}
+
+func TestStore(t *testing.T) {
+
+	const content string = "apple"
+
+	tests := []struct{
+		Store *memdigest.Store
+		ExpectedAlgorithm string
+		Expected string
+		ExpectedLocation string
+	}{
+		{
+			Store: memdigest.NewSHA224(),
+			ExpectedAlgorithm: "SHA-224",
+			Expected: "b7bbfdf1a1012999b3c466fdeb906a629caa5e3e022428d1eb702281",
+			ExpectedLocation: "memdigest:sha-224:hexadecimal(b7bbfdf1a1012999b3c466fdeb906a629caa5e3e022428d1eb702281)/0",
+		},
+		{
+			Store: memdigest.NewSHA384(),
+			ExpectedAlgorithm: "SHA-384",
+			Expected: "3d8786fcb588c93348756c6429717dc6c374a14f7029362281a3b21dc10250ddf0d0578052749822eb08bc0dc1e68b0f",
+			ExpectedLocation: "memdigest:sha-384:hexadecimal(3d8786fcb588c93348756c6429717dc6c374a14f7029362281a3b21dc10250ddf0d0578052749822eb08bc0dc1e68b0f)/0",
+		},
+		{
+			Store: memdigest.NewSHA512(),
+			ExpectedAlgorithm: "SHA-512",
+			Expected: "844d8779103b94c18f4aa4cc0c3b4474058580a991fba85d3ca698a0bc9e52c5940feb7a65a3a290e17e6b23ee943ecc4f73e7490327245b4fe5d5efb590feb2",
+			ExpectedLocation: "memdigest:sha-512:hexadecimal(844d8779103b94c18f4aa4cc0c3b4474058580a991fba85d3ca698a0bc9e52c5940feb7a65a3a290e17e6b23ee943ecc4f73e7490327245b4fe5d5efb590feb2)/0",
+		},
+		{
+			Store: memdigest.NewSHA512_256(),
+			ExpectedAlgorithm: "SHA-512/256",
+			Expected: "1f0f662e561e1d4abf27f945db3c5a8305006138161aad4b9933c4a02964ee54",
+			ExpectedLocation: "memdigest:sha-512/256:hexadecimal(1f0f662e561e1d4abf27f945db3c5a8305006138161aad4b9933c4a02964ee54)/0",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		algorithm, digest, err := test.Store.Create([]byte(content))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect to get an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedAlgorithm, algorithm; expected != actual {
+			t.Errorf("For test #%d, the algorithm that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		if expected, actual := test.Expected, fmt.Sprintf("%x", digest); expected != actual {
+			t.Errorf("For test #%d, the digest that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		{
+			expectedDigest, err := hex.DecodeString(test.Expected)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				continue
+			}
+
+			value, found := test.Store.Load(expectedDigest)
+			if !found {
+				t.Errorf("For test #%d, expected value to exist for the digest.", testNumber)
+				t.Logf("Digest: %s", test.Expected)
+				continue
+			}
+			if expected, actual := content, value; expected != actual {
+				t.Errorf("For test #%d, the actual value is not what was expected.", testNumber)
+				t.Logf("EXPECTED: %q", expected)
+				t.Logf("ACTUAL:   %q", actual)
+				continue
+			}
+		}
+
+		{
+			_, err := test.Store.Open("SHA-1", digest)
+			switch err.(type) {
+			case digestfs.UnsupportedAlgorithm:
+				// Nothing here.
+			default:
+				t.Errorf("For test #%d, expected error to be UnsupportedAlgorithm, but actually wasn't: (%T) %q", testNumber, err, err)
+				continue
+			}
+		}
+
+		{
+			contentOpened, err := test.Store.OpenLocation(test.ExpectedLocation)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				t.Logf("Location: %q", test.ExpectedLocation)
+				continue
+			}
+
+			r := io.NewSectionReader(contentOpened, 0, int64(contentOpened.Len()))
+			value, err := ioutil.ReadAll(r)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+				continue
+			}
+
+			if expected, actual := content, string(value); expected != actual {
+				t.Errorf("For test #%d, the actual value is not what was expected.", testNumber)
+				t.Logf("EXPECTED: %q", expected)
+				t.Logf("ACTUAL:   %q", actual)
+				continue
+			}
+		}
+
+		if err := test.Store.Unmount(); nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		{
+			_, err := test.Store.Open(test.ExpectedAlgorithm, digest)
+			switch err.(type) {
+			case digestfs.ContentNotFound:
+				// Nothing here.
+			default:
+				t.Errorf("For test #%d, expected error to be ContentNotFound, but actually wasn't: (%T) %q", testNumber, err, err)
+				continue
+			}
+		}
+	}
+}
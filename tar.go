@@ -0,0 +1,138 @@
+package memdigest
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// tarDirectory returns the name of the top-level directory of a tar archive written by ExportTar (ex: "sha-1").
+//
+// This is the lowercase algorithm name, as used by OpenLocation, but with any "/" replaced by "-" (ex: "sha-512-256"), so that it is a single directory.
+func (receiver *Store) tarDirectory() string {
+	return strings.ReplaceAll(strings.ToLower(receiver.algorithm), "/", "-")
+}
+
+// ExportTar writes every piece of content in the store to ‘w’, as a tar archive.
+//
+// Each piece of content is a file named after its hexadecimal digest, in a directory named after the first two hexadecimal characters of its digest,
+// in a directory named after the algorithm. For example:
+//
+//	sha-1/d3/486ae9136e7856bc42212385ea797094475802
+//
+// What is written is a consistent view of the store, taken when ExportTar is called.
+func (receiver *Store) ExportTar(w io.Writer) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+
+	directory := receiver.tarDirectory()
+	modTime := time.Unix(0, 0)
+
+	tw := tar.NewWriter(w)
+
+	for _, item := range receiver.items() {
+		digestHexadecimal := hex.EncodeToString([]byte(item.key))
+
+		header := tar.Header{
+			Typeflag: tar.TypeReg,
+			Name: directory + "/" + digestHexadecimal[:2] + "/" + digestHexadecimal[2:],
+			Mode: 0644,
+			Size: int64(len(item.value)),
+			ModTime: modTime,
+		}
+
+		if err := tw.WriteHeader(&header); nil != err {
+			return err
+		}
+		if _, err := io.WriteString(tw, item.value); nil != err {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// ImportTar reads a tar archive (as written by ExportTar) from ‘r’, stores every piece of content in it,
+// and returns how many pieces of content were in it.
+//
+// The digest of every file is re-calculated, and checked against the name of the file.
+// If any file does not match its name, or is not named as ExportTar names files, ImportTar returns an error that says which, and stores nothing.
+//
+// Directories in the tar archive are ignored.
+func (receiver *Store) ImportTar(r io.Reader) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+	if receiver.frozen.Load() {
+		return 0, ErrReadOnly
+	}
+
+	prefix := receiver.tarDirectory() + "/"
+
+	var items []item
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return 0, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+			// Nothing here.
+		default:
+			return 0, fmt.Errorf("memdigest: Bad Tar Entry: %q: not a regular file", header.Name)
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if !strings.HasPrefix(name, prefix) {
+			return 0, fmt.Errorf("memdigest: Bad Tar Entry: %q: not in the %q directory", header.Name, prefix)
+		}
+
+		var digest []byte
+		{
+			s := name[len(prefix):]
+
+			if len(s) < 3 || '/' != s[2] {
+				return 0, fmt.Errorf("memdigest: Bad Tar Entry: %q: not named after a hexadecimal digest", header.Name)
+			}
+
+			digest, err = hex.DecodeString(s[:2] + s[3:])
+			if nil != err || receiver.size != len(digest) {
+				return 0, fmt.Errorf("memdigest: Bad Tar Entry: %q: not named after a hexadecimal digest", header.Name)
+			}
+		}
+
+		var content strings.Builder
+		h := receiver.newHash()
+
+		if _, err := io.Copy(io.MultiWriter(&content, h), tr); nil != err {
+			return 0, err
+		}
+
+		if actual := h.Sum(nil); !bytes.Equal(digest, actual) {
+			return 0, fmt.Errorf("memdigest: Bad Tar Entry: %q: digest mismatch: the content actually has %x", header.Name, actual)
+		}
+
+		items = append(items, item{key:string(digest), value:content.String()})
+	}
+
+	for _, item := range items {
+		if _, err := receiver.insert(item.key, item.value, 0); nil != err {
+			return 0, err
+		}
+	}
+
+	return len(items), nil
+}
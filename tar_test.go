@@ -0,0 +1,160 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"archive/tar"
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"testing"
+)
+
+func TestSHA1ExportTar(t *testing.T) {
+
+	var mem memdigest.SHA1
+	mem.Store([]byte("Hello world!"))
+	mem.Store([]byte("apple"))
+
+	var buffer bytes.Buffer
+	if err := mem.ExportTar(&buffer); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	var actual []string
+	{
+		tr := tar.NewReader(&buffer)
+		for {
+			header, err := tr.Next()
+			if io.EOF == err {
+				break
+			}
+			if nil != err {
+				t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+			}
+
+			content, _ := io.ReadAll(tr)
+			actual = append(actual, header.Name+" "+string(content))
+		}
+	}
+
+	expected := []string{
+		"sha-1/d0/be2dc421be4fcd0172e5afceea3970e2f3d940 apple",
+		"sha-1/d3/486ae9136e7856bc42212385ea797094475802 Hello world!",
+	}
+	sort.Strings(actual)
+
+	if expected, actual := strings.Join(expected, "\n"), strings.Join(actual, "\n"); expected != actual {
+		t.Errorf("The tar archive entries that were actually written were not what was expected.")
+		t.Logf("EXPECTED:\n%s", expected)
+		t.Logf("ACTUAL:\n%s", actual)
+	}
+}
+
+func TestSHA1ImportTar(t *testing.T) {
+
+	var original memdigest.SHA1
+	original.Store([]byte("Hello world!"))
+	original.Store([]byte("apple"))
+	original.Store([]byte(""))
+
+	var buffer bytes.Buffer
+	if err := original.ExportTar(&buffer); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	var restored memdigest.SHA1
+
+	n, err := restored.ImportTar(&buffer)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	if expected, actual := 3, n; expected != actual {
+		t.Errorf("The number of imported entries that was actually returned was not what was expected.")
+		t.Logf("EXPECTED: %d", expected)
+		t.Logf("ACTUAL:   %d", actual)
+	}
+
+	for digest := range original.All() {
+		expected, _ := original.Load(digest[:])
+		actual, found := restored.Load(digest[:])
+		if !found {
+			t.Errorf("Expected imported content to exist, but it actually didn't: %x", digest)
+			continue
+		}
+		if expected != actual {
+			t.Errorf("The imported content was not what was expected.")
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+		}
+	}
+}
+
+func TestSHA1ImportTarBad(t *testing.T) {
+
+	tests := []struct{
+		Name string
+		Content string
+	}{
+		{
+			Name: "sha-1/d3/486ae9136e7856bc42212385ea797094475802",
+			Content: "Hello world?",
+		},
+		{
+			Name: "sha-256/d3/486ae9136e7856bc42212385ea797094475802",
+			Content: "Hello world!",
+		},
+		{
+			Name: "sha-1/d3486ae9136e7856bc42212385ea797094475802",
+			Content: "Hello world!",
+		},
+		{
+			Name: "sha-1/d3/486ae9136e7856bc42212385ea7970944758",
+			Content: "Hello world!",
+		},
+		{
+			Name: "sha-1/d3/486ae9136e7856bc42212385ea79709447580g",
+			Content: "Hello world!",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var buffer bytes.Buffer
+		{
+			tw := tar.NewWriter(&buffer)
+
+			// A good entry, before the bad one, which should not get stored either.
+			tw.WriteHeader(&tar.Header{Typeflag:tar.TypeReg, Name:"sha-1/d0/be2dc421be4fcd0172e5afceea3970e2f3d940", Mode:0644, Size:int64(len("apple"))})
+			io.WriteString(tw, "apple")
+
+			tw.WriteHeader(&tar.Header{Typeflag:tar.TypeReg, Name:test.Name, Mode:0644, Size:int64(len(test.Content))})
+			io.WriteString(tw, test.Content)
+
+			tw.Close()
+		}
+
+		var mem memdigest.SHA1
+
+		_, err := mem.ImportTar(&buffer)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			t.Logf("NAME: %q", test.Name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.Name) {
+			t.Errorf("For test #%d, expected the error to name the bad entry, but it actually didn't.", testNumber)
+			t.Logf("NAME: %q", test.Name)
+			t.Logf("ERROR: %s", err)
+			continue
+		}
+
+		if expected, actual := 0, mem.Stats().Blobs; expected != actual {
+			t.Errorf("For test #%d, did not expect anything from a bad tar archive to be stored, but %d blob(s) actually were.", testNumber, actual)
+			continue
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package memdigest
+
+import (
+	"sync"
+	"time"
+)
+
+// janitor is a goroutine that periodically removes expired content from a Store.
+type janitor struct {
+	mutex sync.Mutex
+	done chan struct{}
+	stopped chan struct{}
+}
+
+// start starts the janitor (stopping any janitor that was already running), calling ‘sweep’ every ‘interval’.
+func (receiver *janitor) start(interval time.Duration, sweep func()) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	receiver.stopLocked()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	receiver.done = done
+	receiver.stopped = stopped
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}()
+}
+
+// stop stops the janitor (if it is running), and waits for it to finish.
+func (receiver *janitor) stop() {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	receiver.stopLocked()
+}
+
+func (receiver *janitor) stopLocked() {
+	if nil == receiver.done {
+		return
+	}
+
+	close(receiver.done)
+	<-receiver.stopped
+
+	receiver.done = nil
+	receiver.stopped = nil
+}
+
+// StoreWithTTL stores ‘content’ and returns the digest of ‘content’.
+//
+// The content expires ‘ttl’ after it was stored, or after it was last read (with Load, Open, or OpenLocation), whichever is later.
+// Expired content is removed the next time someone tries to read it, or by the janitor (see StartJanitor).
+//
+// If ‘content’ was already stored, then it is kept for at least ‘ttl’ more.
+// (Content that was stored with Store, and so never expires, continues to never expire.)
+//
+// The returned digest is in binary form, not hexadecimal.
+func (receiver *Store) StoreWithTTL(content []byte, ttl time.Duration) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+	if ttl <= 0 {
+		return "", errNonPositiveTTL
+	}
+
+	digest, _, err := receiver.store(content, ttl)
+
+	return digest, err
+}
+
+// Sweep removes all the content that has expired. (If the store is frozen, see Freeze, then Sweep does nothing.)
+func (receiver *Store) Sweep() {
+	if nil == receiver {
+		return
+	}
+	if receiver.frozen.Load() {
+		return
+	}
+
+	now := time.Now()
+
+	for i := range receiver.shards {
+		receiver.shards[i].sweep(now, &receiver.lru)
+	}
+}
+
+// StartJanitor starts a goroutine that calls Sweep every ‘interval’.
+//
+// Calling StartJanitor again replaces the previous janitor. The janitor is stopped by StopJanitor, or by Unmount.
+func (receiver *Store) StartJanitor(interval time.Duration) error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if interval <= 0 {
+		return errNonPositiveInterval
+	}
+
+	receiver.janitor.start(interval, receiver.Sweep)
+
+	return nil
+}
+
+// StopJanitor stops the goroutine started by StartJanitor, if there is one.
+func (receiver *Store) StopJanitor() {
+	if nil == receiver {
+		return
+	}
+
+	receiver.janitor.stop()
+}
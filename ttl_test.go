@@ -0,0 +1,167 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"github.com/reiver/go-digestfs"
+
+	"fmt"
+	"sync"
+
+	"testing"
+	"time"
+)
+
+// These tests only ever sleep for longer than a TTL to check that content has expired (which a delay cannot break),
+// and use TTLs that are much longer than the test takes to check that content has not expired.
+
+func TestSHA1StoreWithTTL(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	const ttl = 50 * time.Millisecond
+
+	expiring, err := mem.StoreWithTTL([]byte("apple"), ttl)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	lasting, err := mem.StoreWithTTL([]byte("Cherry"), time.Hour)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	permanent, err := mem.Store([]byte("BANANA"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	time.Sleep(2 * ttl)
+
+	if _, found := mem.Load(expiring[:]); found {
+		t.Errorf("Did not expect content to exist after its TTL passed, but it actually did.")
+	}
+
+	{
+		_, err := mem.Open("SHA-1", string(expiring[:]))
+		switch err.(type) {
+		case digestfs.ContentNotFound:
+			// Nothing here.
+		default:
+			t.Errorf("Expected error to be ContentNotFound, but actually wasn't: (%T) %q", err, err)
+		}
+	}
+
+	if _, found := mem.Load(lasting[:]); !found {
+		t.Errorf("Expected content to exist before its TTL passed, but it actually didn't.")
+	}
+
+	if _, found := mem.Load(permanent[:]); !found {
+		t.Errorf("Expected content stored without a TTL to still exist, but it actually didn't.")
+	}
+
+	if _, err := mem.StoreWithTTL([]byte("dATE"), 0); nil == err {
+		t.Errorf("Expected an error for a TTL of zero, but did not actually get one.")
+	}
+}
+
+func TestSHA1StoreWithTTLReadExtends(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	const ttl = 500 * time.Millisecond
+
+	digest, err := mem.StoreWithTTL([]byte("apple"), ttl)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	// The content is read every ‘ttl’/10 (so that a read would have to be delayed by most of ‘ttl’ for the content to expire),
+	// until it has lived for longer than ‘ttl’ (which it only can by its reads extending it).
+	start := time.Now()
+	for i := 0; time.Since(start) < 2 * ttl; i++ {
+		time.Sleep(ttl / 10)
+
+		if _, found := mem.Load(digest[:]); !found {
+			t.Fatalf("For read #%d, expected content that keeps being read to still exist, but it actually didn't.", i)
+		}
+	}
+}
+
+func TestSHA1StoreWithTTLConcurrentStore(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	const ttl = 50 * time.Millisecond
+
+	// Each piece of content is stored with a TTL, and then, at the same time, stored again without one (which makes it never expire)
+	// and stored again with a longer TTL (which must not undo that).
+	var digests [][20]byte
+	for i := 0; i < 200; i++ {
+		content := []byte(fmt.Sprintf("content #%d", i))
+
+		digest, err := mem.StoreWithTTL(content, ttl)
+		if nil != err {
+			t.Fatalf("For content #%d, did not expect an error, but actually got one: (%T) %q", i, err, err)
+		}
+		digests = append(digests, digest)
+
+		var waitGroup sync.WaitGroup
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			mem.Store(content)
+		}()
+		go func() {
+			defer waitGroup.Done()
+			mem.StoreWithTTL(content, 2 * ttl)
+		}()
+		waitGroup.Wait()
+	}
+
+	time.Sleep(4 * ttl)
+
+	for i, digest := range digests {
+		if _, found := mem.Load(digest[:]); !found {
+			t.Errorf("For content #%d, expected content that was stored without a TTL to still exist, but it actually didn't.", i)
+		}
+	}
+}
+
+func TestSHA1StartJanitor(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	// With a limit of 1 entry, storing a second piece of content evicts the first, unless the janitor already removed it.
+	mem.SetLimits(0, 1)
+
+	var evicted int
+	mem.OnEvict(func([20]byte, int) {
+		evicted++
+	})
+
+	if _, err := mem.StoreWithTTL([]byte("apple"), 10 * time.Millisecond); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if err := mem.StartJanitor(5 * time.Millisecond); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+	defer mem.Unmount()
+
+	// (Rather than sleeping for a fixed time, this waits, for up to much longer than it should take, for the janitor to remove the content.)
+	for deadline := time.Now().Add(10 * time.Second); 0 < mem.Stats().Blobs; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the janitor to remove the expired content, but it actually didn't.")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := mem.Store([]byte("BANANA")); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if expected, actual := 0, evicted; expected != actual {
+		t.Errorf("Expected the janitor to have already removed the expired content, but it actually hadn't.")
+		t.Logf("EXPECTED EVICTIONS: %d", expected)
+		t.Logf("ACTUAL EVICTIONS:   %d", actual)
+	}
+}
@@ -0,0 +1,193 @@
+package memdigest
+
+import (
+	"hash"
+	"io"
+	"strings"
+)
+
+// Writer stores the content written to it, and calculates its digest while it is being written.
+//
+// The content is copied straight into the store's own buffer, so (unlike with Store) the caller does
+// not need to have the whole content in memory before storing it.
+//
+// The content is not stored until Close is called. After that, Digest returns its digest.
+//
+// What the store keeps is the Writer's buffer itself, which grows as content is written to it, and so can end up (and stay)
+// bigger than the content. If how long the content is is known beforehand, calling Grow first avoids this.
+//
+// A Writer is created with the Writer method of a store.
+//
+// Example
+//
+//	w := mem.Writer()
+//
+//	_, err := io.Copy(w, r)
+//	if nil != err {
+//		return err
+//	}
+//
+//	err = w.Close()
+//	if nil != err {
+//		return err
+//	}
+//
+//	digest := w.Digest()
+type Writer struct {
+	store *Store
+	hash hash.Hash
+	buffer strings.Builder
+	closed bool
+	digest string
+}
+
+// Writer returns a new *memdigest.Writer, which stores into the store whatever is written to it.
+func (receiver *Store) Writer() *Writer {
+	var writer Writer
+
+	writer.store = receiver
+	if nil != receiver {
+		writer.hash = receiver.newHash()
+	}
+
+	return &writer
+}
+
+// Grow makes room in the Writer's buffer for another ‘n’ bytes, so that writing them does not make the buffer grow again.
+func (receiver *Writer) Grow(n int) {
+	if nil == receiver {
+		return
+	}
+	if receiver.closed {
+		return
+	}
+
+	receiver.buffer.Grow(n)
+}
+
+// Write makes *memdigest.Writer fit the io.Writer interface.
+func (receiver *Writer) Write(p []byte) (int, error) {
+	if nil == receiver {
+		return 0, errNilReceiver
+	}
+	if nil == receiver.store {
+		return 0, errNilReceiver
+	}
+	if receiver.closed {
+		return 0, errWriterClosed
+	}
+
+	receiver.hash.Write(p)
+
+	return receiver.buffer.Write(p)
+}
+
+// Close makes *memdigest.Writer fit the io.Closer interface.
+//
+// Close stores the content that was written. After Close returns, Digest returns the digest of that content.
+func (receiver *Writer) Close() error {
+	if nil == receiver {
+		return errNilReceiver
+	}
+	if nil == receiver.store {
+		return errNilReceiver
+	}
+	if receiver.closed {
+		return errWriterClosed
+	}
+
+	receiver.closed = true
+
+	digest := string(receiver.hash.Sum(nil))
+
+	_, err := receiver.store.insert(digest, receiver.buffer.String(), 0)
+	receiver.buffer = strings.Builder{}
+	if nil != err {
+		return err
+	}
+
+	receiver.digest = digest
+
+	return nil
+}
+
+// Digest returns the digest of the content that was written, once Close has been called.
+//
+// The returned digest is in binary form, not hexadecimal.
+func (receiver *Writer) Digest() string {
+	if nil == receiver {
+		return ""
+	}
+
+	return receiver.digest
+}
+
+// StoreFrom stores the content read from ‘r’ (until io.EOF), and returns the digest of that content.
+//
+// The content is hashed while it is being copied into the store's own buffer, so it does not first need to be read fully into memory.
+//
+// If ‘r’ has a Len method (as, for example, *bytes.Reader, *bytes.Buffer, and *strings.Reader do), then the buffer is made exactly that big up front,
+// so that the store keeps no more memory than the content needs. Otherwise, StoreFromN can be used, if how long the content is is known.
+//
+// The returned digest is in binary form, not hexadecimal.
+func (receiver *Store) StoreFrom(r io.Reader) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+
+	writer := receiver.Writer()
+
+	if lener, casted := r.(interface{ Len() int }); casted {
+		writer.Grow(lener.Len())
+	}
+
+	_, err := io.Copy(writer, r)
+	if nil != err {
+		return "", err
+	}
+
+	err = writer.Close()
+	if nil != err {
+		return "", err
+	}
+
+	return writer.Digest(), nil
+}
+
+// StoreFromN stores the next ‘n’ bytes read from ‘r’, and returns the digest of them.
+//
+// Like StoreFrom, the content is hashed while it is being copied into the store's own buffer; which is made exactly ‘n’ bytes big up front.
+//
+// If ‘r’ ends before ‘n’ bytes are read, then nothing is stored, and io.ErrUnexpectedEOF is returned.
+//
+// The returned digest is in binary form, not hexadecimal.
+//
+// Example
+//
+//	digest, err := mem.StoreFromN(request.Body, request.ContentLength)
+func (receiver *Store) StoreFromN(r io.Reader, n int64) (string, error) {
+	if nil == receiver {
+		return "", errNilReceiver
+	}
+	if n < 0 {
+		return "", errNegativeSize
+	}
+
+	writer := receiver.Writer()
+	writer.Grow(int(n))
+
+	_, err := io.CopyN(writer, r, n)
+	if io.EOF == err {
+		return "", io.ErrUnexpectedEOF
+	}
+	if nil != err {
+		return "", err
+	}
+
+	err = writer.Close()
+	if nil != err {
+		return "", err
+	}
+
+	return writer.Digest(), nil
+}
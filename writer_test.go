@@ -0,0 +1,231 @@
+package memdigest_test
+
+import (
+	"github.com/reiver/go-memdigest"
+
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"testing"
+)
+
+func TestSHA1StoreFrom(t *testing.T) {
+
+	tests := []struct{
+		Content string
+		Expected string
+	}{
+		{
+			Content: "",
+			Expected: "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		},
+		{
+			Content: "Hello world!",
+			Expected: "d3486ae9136e7856bc42212385ea797094475802",
+		},
+		{
+			Content: "😏😐👾🤖😈",
+			Expected: "1af2b71ae04ddb01cc36cc615e64c950a50b04ff",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var mem memdigest.SHA1
+
+		digest, err := mem.StoreFrom(strings.NewReader(test.Content))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, fmt.Sprintf("%x", digest); expected != actual {
+			t.Errorf("For test #%d, the SHA-1 that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+
+		value, found := mem.Load(digest[:])
+		if !found {
+			t.Errorf("For test #%d, expected value to exist for the SHA-1 digest.", testNumber)
+			t.Logf("SHA-1 digest: %s", test.Expected)
+			continue
+		}
+		if expected, actual := test.Content, value; expected != actual {
+			t.Errorf("For test #%d, the actual value is not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}
+
+func TestSHA1Writer(t *testing.T) {
+
+	var mem memdigest.SHA1
+
+	w := mem.Writer()
+
+	for _, s := range []string{"Hello", " ", "world", "!"} {
+		if _, err := io.WriteString(w, s); nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+		}
+	}
+
+	if expected, actual := "", w.Digest(); expected != actual {
+		t.Errorf("Did not expect a digest before Close, but actually got one: %x", actual)
+	}
+
+	if err := w.Close(); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: (%T) %q", err, err)
+	}
+
+	if expected, actual := "d3486ae9136e7856bc42212385ea797094475802", fmt.Sprintf("%x", w.Digest()); expected != actual {
+		t.Errorf("The SHA-1 that was actually gotten was not what was expected.")
+		t.Logf("EXPECTED: %q", expected)
+		t.Logf("ACTUAL:   %q", actual)
+	}
+
+	if value, found := mem.Load([]byte(w.Digest())); !found || "Hello world!" != value {
+		t.Errorf("Expected the written content to be stored, but it actually wasn't: found=%t value=%q", found, value)
+	}
+
+	if _, err := io.WriteString(w, "more"); nil == err {
+		t.Errorf("Expected an error writing to a closed Writer, but did not actually get one.")
+	}
+}
+
+// lenReader is a reader that has a Len method, but (unlike *bytes.Reader) not a WriteTo method; so io.Copy reads from it a piece at a time.
+type lenReader struct {
+	reader *bytes.Reader
+}
+
+func (receiver lenReader) Len() int {
+	return receiver.reader.Len()
+}
+
+func (receiver lenReader) Read(p []byte) (int, error) {
+	return receiver.reader.Read(p)
+}
+
+// heapAlloc returns how many bytes are allocated on the heap, after a garbage collection.
+func heapAlloc() uint64 {
+	runtime.GC()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapAlloc
+}
+
+func TestSHA1StoreFromRetainedMemory(t *testing.T) {
+
+	// (A buffer that grows as 9 MiB is written to it, a piece at a time, ends up about 11 MiB big.)
+	const size = 9 << 20
+
+	tests := []struct{
+		Name string
+		Store func(*memdigest.SHA1, []byte) ([20]byte, error)
+	}{
+		{
+			Name: "StoreFrom with a reader that has a Len method",
+			Store: func(mem *memdigest.SHA1, content []byte) ([20]byte, error) {
+				return mem.StoreFrom(lenReader{bytes.NewReader(content)})
+			},
+		},
+		{
+			Name: "StoreFromN",
+			Store: func(mem *memdigest.SHA1, content []byte) ([20]byte, error) {
+				return mem.StoreFromN(struct{ io.Reader }{bytes.NewReader(content)}, int64(len(content)))
+			},
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var mem memdigest.SHA1
+
+		before := heapAlloc()
+
+		content := bytes.Repeat([]byte{byte(testNumber)}, size)
+
+		digest, err := test.Store(&mem, content)
+		if nil != err {
+			t.Errorf("For test #%d (%s), did not expect an error, but actually got one: (%T) %q", testNumber, test.Name, err, err)
+			continue
+		}
+
+		content = nil
+		retained := int64(heapAlloc()) - int64(before)
+
+		// (What is retained is compared with 1 1/16 times the size of the content, to leave room for other allocations.)
+		if limit := int64(size + size/16); limit < retained {
+			t.Errorf("For test #%d (%s), the store actually retained more memory than expected.", testNumber, test.Name)
+			t.Logf("EXPECTED: at most %d bytes", limit)
+			t.Logf("ACTUAL:   %d bytes", retained)
+		}
+
+		if value, found := mem.Load(digest[:]); !found || size != len(value) {
+			t.Errorf("For test #%d (%s), expected the content to be stored, but it actually wasn't.", testNumber, test.Name)
+		}
+	}
+}
+
+func TestSHA1StoreFromN(t *testing.T) {
+
+	tests := []struct{
+		Content string
+		N int64
+		Expected string
+		ExpectedError error
+	}{
+		{
+			Content: "Hello world!",
+			N: 12,
+			Expected: "d3486ae9136e7856bc42212385ea797094475802",
+		},
+		{
+			Content: "Hello world! And more.",
+			N: 12,
+			Expected: "d3486ae9136e7856bc42212385ea797094475802",
+		},
+		{
+			Content: "Hello",
+			N: 12,
+			ExpectedError: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		var mem memdigest.SHA1
+
+		digest, err := mem.StoreFromN(strings.NewReader(test.Content), test.N)
+		if nil != test.ExpectedError {
+			if expected, actual := test.ExpectedError, err; expected != actual {
+				t.Errorf("For test #%d, the error that was actually gotten was not what was expected.", testNumber)
+				t.Logf("EXPECTED: %v", expected)
+				t.Logf("ACTUAL:   %v", actual)
+			}
+			if expected, actual := 0, mem.Stats().Blobs; expected != actual {
+				t.Errorf("For test #%d, expected nothing to be stored, but something actually was.", testNumber)
+			}
+			continue
+		}
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, fmt.Sprintf("%x", digest); expected != actual {
+			t.Errorf("For test #%d, the SHA-1 that was actually gotten was not what was expected.", testNumber)
+			t.Logf("EXPECTED: %q", expected)
+			t.Logf("ACTUAL:   %q", actual)
+			continue
+		}
+	}
+}